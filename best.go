@@ -0,0 +1,92 @@
+package main
+
+import (
+		"io"
+		"fmt"
+		"errors"
+		"context"
+		"strconv"
+		"strings"
+		"text/tabwriter"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+// the number of ladders listed by the best command unless given
+const defaultBestListed = 5
+
+// lists the best of the shortest ladders from src to dst; args are the number
+// to list and the weights of the criteria, as name=weight
+func bestCmd(w io.Writer, src string, dst string, args []string) error {
+	if dict == nil {
+		return errNoDictionary
+	}
+	listed := defaultBestListed
+	criteria := dict.DefaultCriteria()
+	for i, arg := range args {
+		name, value, isWeight := strings.Cut(arg, "=")
+		if !isWeight {
+			n, err := strconv.Atoi(arg)
+			if i > 0 || err != nil || n <= 0 {
+				return errUsage
+			}
+			listed = n
+			continue
+		}
+		weight, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s is not a weight", value)
+		}
+		if criteria, err = weigh(criteria, name, weight); err != nil {
+			return err
+		}
+	}
+	ladders, considered, err := dict.BestLadders(context.Background(), src, dst, criteria, listed, searchOptions)
+	switch {
+	case errors.Is(err, dictionary.ErrLengthMismatch):
+		return errors.New("source and destination words are of different lengths, which is outside the problem domain")
+	case errors.Is(err, dictionary.ErrUnknownWord) && !dict.Contains(src):
+		return errors.New("source word not found in dictionary")
+	case errors.Is(err, dictionary.ErrUnknownWord):
+		return errors.New("destination word not found in dictionary")
+	case errors.Is(err, dictionary.ErrNoPath):
+		fmt.Fprintf(w, "No path was found between %s and %s.\n\n", src, dst)
+		return nil
+	case err != nil:
+		return err
+	}
+
+	steps := len(ladders[0].Words) - 1
+	fmt.Fprintf(w, "%d shortest %s of %d %s join %s and %s", considered, plural(considered, "ladder", "ladders"),
+		steps, plural(steps, "step", "steps"), src, dst)
+	if considered == dictionary.MaxRanked {
+		fmt.Fprint(w, " (or more, of which the first alphabetically are ranked)")
+	}
+	var weights []string
+	for _, c := range criteria {
+		weights = append(weights, fmt.Sprintf("%s %g", c.Name, c.Weight))
+	}
+	fmt.Fprintf(w, "; ranked by %s:\n\n", strings.Join(weights, ", "))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprint(tw, "rank\tscore\tladder\n")
+	for i, ladder := range ladders {
+		fmt.Fprintf(tw, "%d\t%.2f\t%s\n", i + 1, ladder.Score, strings.Join(ladder.Words, " "))
+	}
+	tw.Flush()
+	fmt.Fprint(w, "\n")
+	return nil
+}
+
+// criteria with the weight of the one named changed; common words may be
+// weighed even though they are left out without word frequencies
+func weigh(criteria dictionary.Criteria, name string, weight float64) (dictionary.Criteria, error) {
+	for i := range criteria {
+		if criteria[i].Name == name {
+			criteria[i].Weight = weight
+			return criteria, nil
+		}
+	}
+	if name == "common" {
+		return append(criteria, dictionary.Criterion{Name: name, Scorer: dict.CommonWords(), Weight: weight}), nil
+	}
+	return nil, fmt.Errorf("there is no criterion %s; please use common, varied or duplicates", name)
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+		"testing"
+		"bytes"
+		"strings"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestBestCmd(t *testing.T) {
+	defer func(saved *dictionary.Dictionary) { dict = saved }(dict)
+	var err error
+	dict, err = dictionary.Load(strings.NewReader("cold cord card ward warm wold word zzzz"), dictionary.Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	var out bytes.Buffer
+	if err = bestCmd(&out, "cold", "warm", []string{"2"}); err != nil {
+		t.Fatalf("bestCmd(cold warm 2), unexpected error=%v", err)
+	}
+	want := "3 shortest ladders of 4 steps join cold and warm; ranked by varied 2, duplicates 2:\n" +
+		"\n" +
+		"rank  score  ladder\n" +
+		"1     2.00   cold cord card ward warm\n" +
+		"2     2.00   cold cord word ward warm\n" +
+		"\n"
+	if out.String() != want {
+		t.Errorf("bestCmd(cold warm 2), want:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	dict.LoadFrequencies(strings.NewReader("card 50\nword 10"))
+	out.Reset()
+	if err = bestCmd(&out, "cold", "warm", []string{"1", "varied=0", "common=2"}); err != nil {
+		t.Fatalf("bestCmd(common=2), unexpected error=%v", err)
+	}
+	if !strings.Contains(out.String(), "ranked by common 2, varied 0, duplicates 2:\n") || !strings.Contains(out.String(), "1     2.62   cold cord card ward warm\n") {
+		t.Errorf("bestCmd(common=2), got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err = bestCmd(&out, "cold", "zzzz", nil); err != nil || out.String() != "No path was found between cold and zzzz.\n\n" {
+		t.Errorf("bestCmd(cold zzzz), got %q, err=%v", out.String(), err)
+	}
+	for _, args := range [][]string{{"cold", "cole"}, {"cole", "cold"}, {"cold", "cat"}, {"cold", "warm", "pretty=1"}, {"cold", "warm", "common=x"}} {
+		if err = bestCmd(&out, args[0], args[1], args[2:]); err == nil {
+			t.Errorf("bestCmd(%v), want error", args)
+		}
+	}
+	if err = bestCmd(&out, "cold", "warm", []string{"varied=1", "3"}); err != errUsage {
+		t.Errorf("bestCmd(varied=1 3), want errUsage, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+		"io"
+		"fmt"
+		"strconv"
+		"text/tabwriter"
+		"github.com/nerophon/dictdash/grapher"
+)
+
+// the number of words ranked by the central command unless given
+const defaultCentralListed = 10
+
+// ranks the words of the largest component of one length, or of the largest
+// component of all, by betweenness centrality; args are the length, the number
+// of words to rank, and the options samples=k (or all), seed=s and csv=file,
+// which writes every word's centrality to file
+func centralCmd(w io.Writer, args []string) error {
+	if dict == nil {
+		return errNoDictionary
+	}
+	letterCount, listed := 0, defaultCentralListed
+	s := newSampling()
+	positional := 0
+	for _, arg := range args {
+		isOption, err := s.parse(arg)
+		switch {
+		case err != nil:
+			return err
+		case isOption:
+		case positional == 0:
+			letterCount, err = strconv.Atoi(arg)
+			if err != nil || letterCount <= 0 {
+				return fmt.Errorf("%s is not a word length", arg)
+			}
+			positional++
+		case positional == 1:
+			listed, err = strconv.Atoi(arg)
+			if err != nil || listed <= 0 {
+				return fmt.Errorf("%s is not a number of words", arg)
+			}
+			positional++
+		default:
+			return errUsage
+		}
+	}
+	nodes := largestComponent(dict.Graph(), letterCount)
+	if len(nodes) == 0 {
+		return fmt.Errorf("there are no words of length %d", letterCount)
+	}
+	centralities := grapher.Centralities(nodes, s.among(len(nodes)))
+	writeAll := func(out io.Writer) error { return grapher.WriteCentralityCSV(out, centralities) }
+	if csvOutput {
+		if err := grapher.WriteCentralityCSV(w, centralities[:min(len(centralities), listed)]); err != nil {
+			return err
+		}
+		return s.writeCSV(io.Discard, "", writeAll)
+	}
+
+	fmt.Fprintf(w, "Centrality in the component of %d %s of length %d containing %s%s.\n",
+		len(nodes), plural(len(nodes), "word", "words"), len(nodes[0].Word), nodes[0].Word, s.describe(len(nodes)))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprint(tw, "\nrank\tword\tbetweenness\tcloseness\n")
+	for i, c := range centralities[:min(len(centralities), listed)] {
+		fmt.Fprintf(tw, "%d\t%s\t%.1f\t%.4f\n", i + 1, c.Node.Word, c.Betweenness, c.Closeness)
+	}
+	tw.Flush()
+	what := fmt.Sprintf("the centrality of %d %s", len(centralities), plural(len(centralities), "word", "words"))
+	if err := s.writeCSV(w, what, writeAll); err != nil {
+		return err
+	}
+	fmt.Fprint(w, "\n")
+	return nil
+}
+
+// the largest component of words of the given length, or of any length if
+// letterCount is zero; the first in alphabetical order of those equally large
+func largestComponent(graph grapher.WordGraph, letterCount int) (largest []*grapher.WordNode) {
+	for _, component := range graph.Components() {
+		if (letterCount == 0 || len(component[0].Word) == letterCount) && len(component) > len(largest) {
+			largest = component
+		}
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+		"testing"
+		"bytes"
+		"os"
+		"strings"
+		"path/filepath"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestCentralCmd(t *testing.T) {
+	defer func(saved *dictionary.Dictionary) { dict = saved }(dict)
+	var err error
+	dict, err = dictionary.Load(strings.NewReader("cold cord card ward warm cab cat cot cob"), dictionary.Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	var out bytes.Buffer
+	if err = centralCmd(&out, []string{"4", "3"}); err != nil {
+		t.Fatalf("centralCmd(4 3), unexpected error=%v", err)
+	}
+	want := "Centrality in the component of 5 words of length 4 containing card.\n" +
+		"\n" +
+		"rank  word  betweenness  closeness\n" +
+		"1     card  4.0          0.6667\n" +
+		"2     cord  3.0          0.5714\n" +
+		"3     ward  3.0          0.5714\n" +
+		"\n"
+	if out.String() != want {
+		t.Errorf("centralCmd(4 3), want:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	out.Reset()
+	path := filepath.Join(t.TempDir(), "central.csv")
+	if err = centralCmd(&out, []string{"3", "samples=2", "seed=5", "csv=" + path}); err != nil {
+		t.Fatalf("centralCmd(3 samples=2), unexpected error=%v", err)
+	}
+	if !strings.HasPrefix(out.String(), "Centrality in the component of 4 words of length 3 containing cab, estimated from 2 of them with seed 5.\n") {
+		t.Errorf("centralCmd(3 samples=2), got:\n%s", out.String())
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), "word,betweenness,closeness\n") || strings.Count(string(data), "\n") != 5 {
+		t.Errorf("centralCmd(csv=), wrote %q, err=%v", data, err)
+	}
+
+	out.Reset()
+	if err = centralCmd(&out, nil); err != nil || !strings.Contains(out.String(), "5 words of length 4") {
+		t.Errorf("centralCmd(), want the largest component, got:\n%s", out.String())
+	}
+	for _, args := range [][]string{{"x"}, {"4", "0"}, {"7"}, {"4", "3", "samples=no"}, {"4", "3", "9"}} {
+		if err = centralCmd(&out, args); err == nil {
+			t.Errorf("centralCmd(%v), want error", args)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+		"io"
+		"os"
+		"fmt"
+		"sort"
+		"slices"
+		"errors"
+		"strings"
+		"path/filepath"
+		"text/tabwriter"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+// the REPL's commands are kept in a registry, which checks each command's
+// arguments against its specification before running it, and generates
+// the help text and tab completions from the same specifications
+
+// the kinds of argument, which determine how they are completed
+type argKind int
+
+const (
+	argText argKind = iota // anything, not completed
+	argWord // a dictionary word, or a comma-separated list of them
+	argPath // a file
+	argChoice // one of a fixed set of keywords
+	argCommand // the name of a command
+	argDictionary // the name of a loaded dictionary
+	argDictionaryOrPath // the name of a loaded dictionary, or a file
+)
+
+// one argument of a command
+type argSpec struct {
+	name string // shown in help; unnamed argChoice arguments show their choices instead
+	kind argKind
+	choices []string
+	optional bool
+	repeated bool // takes every remaining argument; only the last may be
+}
+
+// shown in help as <name>, choice|choice, or in brackets if optional
+func (a argSpec) String() string {
+	s := "<" + a.name + ">"
+	if a.kind == argChoice && a.name == "" {
+		s = strings.Join(a.choices, "|")
+	}
+	if a.repeated {
+		s += "..."
+	}
+	if a.optional {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+// returned by a command's run function when its arguments are wrong,
+// so that its usage is shown
+var errUsage = errors.New("usage")
+
+// returned by the quit command, to end the command loop
+var errQuit = errors.New("quit")
+
+type command struct {
+	name string
+	args []argSpec
+	help string // a one-line summary
+	details []string // further lines for help on this command alone
+	run func(args []string) error
+}
+
+// the command's name and arguments, as shown in help
+func (c *command) usage() string {
+	parts := []string{c.name}
+	for _, a := range c.args {
+		parts = append(parts, a.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// the fewest and most arguments the command takes; max is -1 if unbounded
+func (c *command) argCounts() (min, max int) {
+	for _, a := range c.args {
+		if !a.optional {
+			min++
+		}
+		if a.repeated {
+			max = -1
+		} else if max >= 0 {
+			max++
+		}
+	}
+	return
+}
+
+// the arguments that may appear at position, given that optional
+// arguments may be left out
+func (c *command) argsAt(position int) (args []argSpec) {
+	required := 0 // before the current argument
+	for i, a := range c.args {
+		if required <= position && (position <= i || a.repeated) {
+			args = append(args, a)
+		}
+		if !a.optional {
+			required++
+		}
+	}
+	return
+}
+
+// the commands in the order they were added, which is the order of help
+type registry struct {
+	commands []*command
+	byName map[string]*command
+}
+
+func newRegistry() *registry {
+	return &registry{byName: make(map[string]*command)}
+}
+
+// panics if a command of the same name was already added
+func (r *registry) add(c *command) {
+	if r.byName[c.name] != nil {
+		panic("dictdash: command " + c.name + " added twice")
+	}
+	r.commands = append(r.commands, c)
+	r.byName[c.name] = c
+}
+
+// runs the command on line; blank lines and comments do nothing,
+// and errQuit is returned by the quit command
+func (r *registry) execute(line string) error {
+	fields := strings.Fields(stripComment(line))
+	if len(fields) == 0 {
+		return nil
+	}
+	c := r.byName[fields[0]]
+	if c == nil {
+		return fmt.Errorf("command %s not understood; typing \"help\" will show a command list", fields[0])
+	}
+	args := fields[1:]
+	min, max := c.argCounts()
+	err := errUsage
+	if len(args) >= min && (max < 0 || len(args) <= max) {
+		err = c.run(args)
+	}
+	if err == errUsage {
+		return fmt.Errorf("usage: %s", c.usage())
+	}
+	return err
+}
+
+// writes the command list, or every form of the commands named in args
+func (r *registry) help(w io.Writer, args []string) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	if len(args) == 0 {
+		fmt.Fprint(tw, "\n***Command List***\n\n")
+		for _, c := range r.commands {
+			fmt.Fprintf(tw, "%s\t%s\n", c.usage(), c.help)
+		}
+		fmt.Fprint(tw, "\nTyping \"help <command>\" shows more about a command.\n\n")
+		return tw.Flush()
+	}
+	for _, name := range args {
+		c := r.byName[name]
+		if c == nil {
+			return fmt.Errorf("there is no %s command", name)
+		}
+		fmt.Fprintf(tw, "\n%s\n\t%s\n", c.usage(), c.help)
+		for _, line := range c.details {
+			fmt.Fprintf(tw, "\t%s\n", line)
+		}
+	}
+	fmt.Fprint(tw, "\n")
+	return tw.Flush()
+}
+
+// the completions of the last field of before, which is the text to the
+// left of the cursor, and the byte offset at which that field starts
+func (r *registry) complete(before string) (start int, candidates []string) {
+	start = strings.LastIndexAny(before, " \t") + 1
+	fields := strings.Fields(before[:start])
+	prefix := before[start:]
+	var args []argSpec
+	if len(fields) == 0 {
+		args = []argSpec{{kind: argCommand}}
+	} else if c := r.byName[fields[0]]; c != nil {
+		args = c.argsAt(len(fields) - 1)
+	}
+	for _, a := range args {
+		switch a.kind {
+		case argWord:
+			// only the last of a comma-separated list is completed
+			comma := strings.LastIndex(prefix, ",") + 1
+			for _, word := range completeWords(prefix[comma:]) {
+				candidates = append(candidates, prefix[:comma] + word)
+			}
+		case argPath:
+			candidates = append(candidates, completePath(prefix)...)
+		case argChoice:
+			candidates = append(candidates, withPrefix(a.choices, prefix)...)
+		case argCommand:
+			candidates = append(candidates, r.commandNames(prefix)...)
+		case argDictionary:
+			candidates = append(candidates, withPrefix(dictionaryNames(), prefix)...)
+		case argDictionaryOrPath:
+			candidates = append(candidates, withPrefix(dictionaryNames(), prefix)...)
+			candidates = append(candidates, completePath(prefix)...)
+		}
+	}
+	sort.Strings(candidates)
+	return start, slices.Compact(candidates)
+}
+
+func (r *registry) commandNames(prefix string) []string {
+	names := make([]string, len(r.commands))
+	for i, c := range r.commands {
+		names[i] = c.name
+	}
+	return withPrefix(names, prefix)
+}
+
+func withPrefix(choices []string, prefix string) (matching []string) {
+	for _, choice := range choices {
+		if strings.HasPrefix(choice, prefix) {
+			matching = append(matching, choice)
+		}
+	}
+	return
+}
+
+// the words of the current dictionary in alphabetical order,
+// rebuilt whenever another dictionary is scanned
+var sortedWords struct {
+	dict *dictionary.Dictionary
+	words []string
+}
+
+// the dictionary words starting with prefix
+func completeWords(prefix string) []string {
+	if dict == nil {
+		return nil
+	}
+	if sortedWords.dict != dict {
+		sortedWords.words = sortedWords.words[:0]
+		for _, subGraph := range dict.Graph() {
+			for word := range subGraph {
+				sortedWords.words = append(sortedWords.words, word)
+			}
+		}
+		sort.Strings(sortedWords.words)
+		sortedWords.dict = dict
+	}
+	words := sortedWords.words
+	i := sort.SearchStrings(words, prefix)
+	j := i
+	for j < len(words) && strings.HasPrefix(words[j], prefix) {
+		j++
+	}
+	return words[i:j]
+}
+
+// the files and directories starting with prefix, directories ending in '/'
+func completePath(prefix string) (paths []string) {
+	dir, base := filepath.Split(prefix)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+		if entry.IsDir() {
+			name += "/"
+		}
+		paths = append(paths, dir + name)
+	}
+	return
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+		"testing"
+		"bytes"
+		"strings"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestRegistry(t *testing.T) {
+	var ran []string
+	r := newRegistry()
+	r.add(&command{
+		name: "pair",
+		args: []argSpec{{kind: argChoice, choices: []string{"--x"}, optional: true}, {name: "A", kind: argWord}, {name: "B", kind: argWord}},
+		help: "takes a pair",
+		details: []string{"more about pairs"},
+		run: func(args []string) error {
+			ran = append(ran, strings.Join(args, ","))
+			if args[0] == "bad" {
+				return errUsage
+			}
+			return nil
+		},
+	})
+	r.add(&command{
+		name: "many",
+		args: []argSpec{{name: "path", kind: argPath}, {name: "arg", kind: argText, repeated: true}},
+		help: "takes several",
+		run: func(args []string) error { return errQuit },
+	})
+	executions := []struct {
+		line string
+		want string // the error, if any
+	}{
+		{"", ""},
+		{"pair a b", ""},
+		{"  pair --x a b ", ""},
+		{"pair a", "usage: pair [--x] <A> <B>"},
+		{"pair a b c d", "usage: pair [--x] <A> <B>"},
+		{"pair bad b", "usage: pair [--x] <A> <B>"},
+		{"many p", "usage: many <path> <arg>..."},
+		{"many p q r", "quit"},
+		{"other", "command other not understood; typing \"help\" will show a command list"},
+	}
+	for _, c := range executions {
+		got := ""
+		if err := r.execute(c.line); err != nil {
+			got = err.Error()
+		}
+		if got != c.want {
+			t.Errorf("execute(%q), want=%q, got=%q", c.line, c.want, got)
+		}
+	}
+	if got, want := strings.Join(ran, " "), "a,b --x,a,b bad,b"; got != want {
+		t.Errorf("execute(), ran want=%q, got=%q", want, got)
+	}
+
+	var help bytes.Buffer
+	if err := r.help(&help, nil); err != nil {
+		t.Fatalf("help(), unexpected error=%v", err)
+	}
+	for _, want := range []string{"pair [--x] <A> <B>    takes a pair\n", "many <path> <arg>...  takes several\n"} {
+		if !strings.Contains(help.String(), want) {
+			t.Errorf("help(), want line %q in:\n%s", want, help.String())
+		}
+	}
+	help.Reset()
+	r.help(&help, []string{"pair"})
+	if want := "\npair [--x] <A> <B>\n  takes a pair\n  more about pairs\n\n"; help.String() != want {
+		t.Errorf("help(pair), want=%q, got=%q", want, help.String())
+	}
+	if err := r.help(&help, []string{"other"}); err == nil {
+		t.Errorf("help(other), want error")
+	}
+}
+
+func TestComplete(t *testing.T) {
+	loaded, err := dictionary.Load(strings.NewReader("cold cord card warm word"), dictionary.Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	saved := dict
+	dict = loaded
+	defer func() { dict = saved }()
+	r := newCommands()
+	cases := []struct {
+		before string
+		start int
+		want string
+	}{
+		{"", 0, "ball best central compare critical degree dictionaries diff doublets echo export frequencies help landmarks lengths load neighbours output quit scan search set tiebreak unload use"},
+		{"s", 0, "scan search set"},
+		{"help sea", 5, "search"},
+		{"set s", 4, "stats stop"},
+		{"search ", 7, "--stats card cold cord warm word"},
+		{"search c", 7, "card cold cord"},
+		{"search --stats wo", 15, "word"},
+		{"search cold,wa", 7, "cold,warm"},
+		{"search cold wa", 12, "warm"},
+		{"tiebreak f", 9, "first freq"},
+		{"tiebreak first ", 15, ""},
+		{"export dot x.dot co", 17, "component"},
+		{"export dot x.dot ladder co", 24, "cold cord"},
+		{"export dot x.dot ladder cold w", 29, "warm word"},
+		{"landmarks ", 10, ""},
+		{"unknown x", 8, ""},
+		{"scan dictdash_t", 5, "dictdash_test.go"},
+		{"scan gr", 5, "grapher/"},
+		{"scan grapher/index_", 5, "grapher/index_test.go"},
+	}
+	for _, c := range cases {
+		start, candidates := r.complete(c.before)
+		if got := strings.Join(candidates, " "); start != c.start || got != c.want {
+			t.Errorf("complete(%q), want=%d %q, got=%d %q", c.before, c.start, c.want, start, got)
+		}
+	}
+}
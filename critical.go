@@ -0,0 +1,79 @@
+package main
+
+import (
+		"io"
+		"fmt"
+		"sort"
+		"strconv"
+		"strings"
+		"text/tabwriter"
+		"github.com/nerophon/dictdash/grapher"
+)
+
+// the most critical words and bridges listed by the critical command
+const maxCriticalListed = 20
+
+// lists the words and edges whose removal would disconnect words of the
+// given length, or of every length if arg is empty, most disruptive first
+func criticalCmd(w io.Writer, arg string) error {
+	if dict == nil {
+		return errNoDictionary
+	}
+	graph := dict.Graph()
+	var lengths []int
+	if arg == "" {
+		for letterCount := range graph {
+			lengths = append(lengths, letterCount)
+		}
+		sort.Ints(lengths)
+	} else {
+		letterCount, err := strconv.Atoi(arg)
+		if err != nil || letterCount <= 0 {
+			return fmt.Errorf("%s is not a word length", arg)
+		}
+		lengths = []int{letterCount}
+	}
+	var articulations []grapher.Articulation
+	var bridges []grapher.Bridge
+	for _, letterCount := range lengths {
+		a, b := graph.Critical(letterCount)
+		articulations, bridges = append(articulations, a...), append(bridges, b...)
+	}
+	// shorter words first where the cut is equal, then as Critical orders them
+	sort.SliceStable(articulations, func(i, j int) bool { return articulations[i].Cut() > articulations[j].Cut() })
+	sort.SliceStable(bridges, func(i, j int) bool { return bridges[i].Cut() > bridges[j].Cut() })
+
+	fmt.Fprintf(w, "%d critical %s and %d %s.\n", len(articulations), plural(len(articulations), "word", "words"),
+		len(bridges), plural(len(bridges), "bridge", "bridges"))
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	if len(articulations) > 0 {
+		fmt.Fprint(tw, "\nword\tcuts off\tpieces left by removing it\n")
+		for _, a := range articulations[:min(len(articulations), maxCriticalListed)] {
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", a.Node.Word, a.Cut(), joinInts(a.Pieces))
+		}
+		tw.Flush()
+		if len(articulations) > maxCriticalListed {
+			fmt.Fprintf(w, "%s\n", more(len(articulations) - maxCriticalListed))
+		}
+	}
+	if len(bridges) > 0 {
+		fmt.Fprint(tw, "\nbridge\tcuts off\tpieces left by removing it\n")
+		for _, b := range bridges[:min(len(bridges), maxCriticalListed)] {
+			fmt.Fprintf(tw, "%s - %s\t%d\t%d %d\n", b.A.Word, b.B.Word, b.Cut(), b.Pieces[0], b.Pieces[1])
+		}
+		tw.Flush()
+		if len(bridges) > maxCriticalListed {
+			fmt.Fprintf(w, "%s\n", more(len(bridges) - maxCriticalListed))
+		}
+	}
+	fmt.Fprint(w, "\n")
+	return nil
+}
+
+func joinInts(values []int) string {
+	s := make([]string, len(values))
+	for i, v := range values {
+		s[i] = strconv.Itoa(v)
+	}
+	return strings.Join(s, " ")
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+		"testing"
+		"bytes"
+		"strings"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestCriticalCmd(t *testing.T) {
+	defer func(saved *dictionary.Dictionary) { dict = saved }(dict)
+	var err error
+	dict, err = dictionary.Load(strings.NewReader("cold cord card ward warm cab cat cot cob hat"), dictionary.Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	var out bytes.Buffer
+	if err = criticalCmd(&out, "4"); err != nil {
+		t.Fatalf("criticalCmd(4), unexpected error=%v", err)
+	}
+	want := "3 critical words and 4 bridges.\n" +
+		"\n" +
+		"word  cuts off  pieces left by removing it\n" +
+		"card  2         2 2\n" +
+		"cord  1         3 1\n" +
+		"ward  1         3 1\n" +
+		"\n" +
+		"bridge       cuts off  pieces left by removing it\n" +
+		"card - cord  2         3 2\n" +
+		"card - ward  2         3 2\n" +
+		"cold - cord  1         1 4\n" +
+		"ward - warm  1         4 1\n" +
+		"\n"
+	if out.String() != want {
+		t.Errorf("criticalCmd(4), want:\n%s\ngot:\n%s", want, out.String())
+	}
+	out.Reset()
+	criticalCmd(&out, "")
+	if !strings.HasPrefix(out.String(), "4 critical words and 5 bridges.\n") || !strings.Contains(out.String(), "card  2         2 2\ncat   1         3 1\n") {
+		t.Errorf("criticalCmd(), got:\n%s", out.String())
+	}
+	if err = criticalCmd(&out, "x"); err == nil {
+		t.Errorf("criticalCmd(x), want error")
+	}
+}
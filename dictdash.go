@@ -8,145 +8,770 @@ package main
 import (
 		"fmt"
 		"strings"
-		"bufio"
+		"errors"
+		"context"
+		"io"
+		"flag"
 		"os"
+		"os/signal"
+		"strconv"
+		"time"
+		"github.com/nerophon/dictdash/dictionary"
 		"github.com/nerophon/dictdash/grapher"
 		"github.com/nerophon/dictdash/search"
 )
 
 
-var graph grapher.WordGraph
+// the dictionary in use, as chosen from dictionaries
+var dict *dictionary.Dictionary
+
+// returned by commands needing a dictionary before one is scanned
+var errNoDictionary = errors.New("there is no dictionary; please scan one first")
+
+// applied to every search, as set by the tiebreak and set commands
+var searchOptions search.Options
+
+// the tiebreak command's names for each search.TieBreak
+var tieBreaks = map[string]search.TieBreak{
+	"first": search.TieFirst,
+	"lex": search.TieLexicographic,
+	"freq": search.TieFrequency,
+	"h": search.TieHigherEstimate,
+	"random": search.TieRandom,
+}
+
+// the set engine command's names for each search.Engine
+var engines = map[string]search.Engine{
+	"astar": search.EngineAStar,
+	"dijkstra": search.EngineDijkstra,
+	"bfs": search.EngineBFS,
+}
+
+// the set cost command's names for each search.CostModel
+var costModels = map[string]search.CostModel{
+	"unit": search.CostGraph,
+	"moves": search.CostWeighted,
+}
 
 func main() {
+	script := flag.String("script", "", "runs the commands in the named file, then quits")
+	flag.BoolVar(&stopOnError, "stop-on-error", false, "stops a script at the first command that fails")
+	flag.Parse()
+	commands := newCommands()
+	if *script != "" {
+		runScriptFile(commands, *script)
+		return
+	}
 	fmt.Print("\nWelcome to Dictionary Dash!\n")
-	reader := bufio.NewReader(os.Stdin)
 	fmt.Print("Please enter a command.\n" +
 		"Typing \"help\" will show a command list.\n\n")
-	commandLoop(reader)
+	editor := newLineEditor(os.Stdin, os.Stdout)
+	editor.complete = commands.complete
+	if editor.edit {
+		if err := editor.loadHistory(historyPath()); err != nil {
+			fmt.Printf("Could not read the command history: %v\n\n", err)
+		}
+	}
+	commandLoop(editor, commands)
 }
 
-func commandLoop(reader *bufio.Reader) {
-	fmt.Print("> ")
-	text, error := reader.ReadString('\n')
-	if error != nil || len(text) == 0 {
-		fmt.Println("Sorry, there was an input error:\n%d", error)
-		return
+func commandLoop(editor *lineEditor, commands *registry) {
+	for {
+		line, err := editor.readLine("> ")
+		if err == io.EOF {
+			fmt.Print("Quitting.\n\n")
+			return
+		}
+		if err != nil {
+			fmt.Printf("Sorry, there was an input error:\n%v\n", err)
+			return
+		}
+		err = commands.execute(line)
+		if err == errQuit {
+			return
+		}
+		if err != nil {
+			fmt.Printf("Sorry, %v.\n\n", err)
+		}
 	}
-	trimmed := strings.Trim(text, "\n ")
-	fields := strings.Fields(trimmed)
-	numFields := len(fields)
-	if numFields <= 0 {
-		commandLoop(reader)
-		return
+}
+
+// every command of the REPL, in the order shown by help
+func newCommands() *registry {
+	r := newRegistry()
+	word := func(name string) argSpec { return argSpec{name: name, kind: argWord} }
+	number := func(name string) argSpec { return argSpec{name: name, kind: argText} }
+	choice := func(optional bool, choices ...string) argSpec {
+		return argSpec{kind: argChoice, choices: choices, optional: optional}
 	}
-	switch fields[0] {
-	case "quit":
-		fmt.Println("Quitting.\n")
-		return
-	case "help":
-		fmt.Println("\n***Command List***\n")
-		fmt.Println("help\t\tshows this command list")
-		fmt.Println("scan\t\tscans a whitespace-delimited dictionary at ./dict.txt")
-		fmt.Println("scan [path]\tscans a whitespace-delimited dictionary at [path]")
-		fmt.Println("search [A] [B]\tsearches for the shortest path from [A] to [B]")
-		fmt.Println("quit\t\tquits the application")
-		fmt.Println("")
-	case "scan":
-		if numFields == 1 {
-			scan("dict.txt")
-		} else if numFields == 2 {
-			scan(fields[1])
-		} else {
-			fmt.Println("Please specify only one path.\n")
-		}
-	case "search":
-		if numFields != 3 {
-			fmt.Println("The search command requires exactly two arguments.\n")
-		} else {
-			searchCmd(fields[1], fields[2])
+	r.add(&command{
+		name: "help",
+		args: []argSpec{{name: "command", kind: argCommand, optional: true}},
+		help: "shows this command list, or more about a command",
+		run: func(args []string) error { return r.help(os.Stdout, args) },
+	})
+	r.add(&command{
+		name: "scan",
+		args: []argSpec{
+			{name: "path", kind: argPath, optional: true},
+			choice(true, "stems"),
+			{name: "moves=list", kind: argText, optional: true},
+		},
+		help: "scans a whitespace-delimited dictionary, ./dict.txt by default, replacing the one in use",
+		details: []string{
+			"a path ending in .dic is read as a Hunspell dictionary, expanded using the .aff file of the same name if present",
+			"stems scans only the unaffixed stems of a Hunspell dictionary",
+			"moves=list also links words by the moves in list, from sub, swap and anagram, such as moves=sub,swap",
+		},
+		run: func(args []string) error { return scanArgs(scanName(), args) },
+	})
+	r.add(&command{
+		name: "search",
+		args: []argSpec{choice(true, "--stats"), word("A"), word("B"), {name: "dictionary", kind: argDictionary, optional: true}},
+		help: "searches for the shortest path from A to B",
+		details: []string{
+			"A and B may be comma-separated lists, such as cold,cool warm,hot, to search from any of the first to any of the second",
+			"--stats shows search statistics after the path",
+			"the search is made in the dictionary in use, unless another loaded dictionary is named",
+		},
+		run: func(args []string) error {
+			showStats := args[0] == "--stats"
+			if showStats {
+				args = args[1:]
+			}
+			d := dict
+			switch len(args) {
+			case 2:
+			case 3:
+				var err error
+				if d, err = namedDictionary(args[2]); err != nil {
+					return err
+				}
+			default:
+				return errUsage
+			}
+			return searchCmd(d, args[0], args[1], showStats || alwaysStats)
+		},
+	})
+	r.add(&command{
+		name: "best",
+		args: []argSpec{word("A"), word("B"), {name: "n", kind: argText, optional: true}, {name: "criterion=weight", kind: argText, optional: true, repeated: true}},
+		help: "ranks the shortest paths from A to B by how pleasant they are, listing the best n",
+		details: []string{
+			"common prefers common words, if frequencies have been read; varied prefers steps changing letters in other places than the step before;",
+			"duplicates avoids steps between spellings of one word, such as jounce to jaunce",
+			"their weights are 1, 2 and 2 unless given, such as common=3 duplicates=0",
+		},
+		run: func(args []string) error { return bestCmd(os.Stdout, args[0], args[1], args[2:]) },
+	})
+	r.add(&command{
+		name: "load",
+		args: []argSpec{
+			{name: "name", kind: argText},
+			{name: "path", kind: argPath, optional: true},
+			choice(true, "stems"),
+			{name: "moves=list", kind: argText, optional: true},
+		},
+		help: "scans a dictionary as scan does, keeping it under name, and puts it in use",
+		details: []string{"other dictionaries already loaded are kept, and can be put back in use by use"},
+		run: func(args []string) error { return scanArgs(args[0], args[1:]) },
+	})
+	r.add(&command{
+		name: "use",
+		args: []argSpec{{name: "name", kind: argDictionary}},
+		help: "puts the named dictionary in use by every other command",
+		run: func(args []string) error {
+			if _, err := namedDictionary(args[0]); err != nil {
+				return err
+			}
+			useDictionary(args[0])
+			fmt.Printf("Using the %s dictionary.\n\n", args[0])
+			return nil
+		},
+	})
+	r.add(&command{
+		name: "dictionaries",
+		help: "lists the loaded dictionaries",
+		run: func(args []string) error { listDictionaries(os.Stdout); return nil },
+	})
+	r.add(&command{
+		name: "unload",
+		args: []argSpec{{name: "name", kind: argDictionary}},
+		help: "forgets the named dictionary, freeing its memory",
+		run: func(args []string) error { return unloadDictionary(args[0]) },
+	})
+	r.add(&command{
+		name: "diff",
+		args: []argSpec{
+			{name: "old", kind: argDictionaryOrPath},
+			{name: "new", kind: argDictionaryOrPath},
+			{name: "puzzles", kind: argPath, optional: true},
+		},
+		help: "shows the words added and removed between two dictionaries, and the components merged or split",
+		details: []string{
+			"old and new may each be the name of a loaded dictionary or a file to scan",
+			"puzzles is a file of word pairs, one pair per line, whose ladders are compared",
+		},
+		run: func(args []string) error {
+			puzzles := ""
+			if len(args) == 3 {
+				puzzles = args[2]
+			}
+			return diffCmd(os.Stdout, args[0], args[1], puzzles)
+		},
+	})
+	r.add(&command{
+		name: "compare",
+		args: []argSpec{word("A"), word("B"), {name: "dictionary", kind: argDictionary, optional: true, repeated: true}},
+		help: "compares the shortest paths from A to B in every loaded dictionary, or those named",
+		run: func(args []string) error { return compareCmd(os.Stdout, args[0], args[1], args[2:]) },
+	})
+	r.add(&command{
+		name: "doublets",
+		args: []argSpec{{name: "pairs", kind: argPath}, {name: "option", kind: argChoice, choices: []string{"markdown", "text", "answers"}, optional: true, repeated: true}},
+		help: "solves the pairs of words listed in a file, and prints them as a book of puzzles",
+		details: []string{
+			"the file lists one pair per line, such as head tail; # starts a comment",
+			"markdown writes the book in Markdown instead of plain text, and answers adds the answers at the end",
+			"dictionary=name solves the puzzles in another loaded dictionary, and out=file writes the book to file",
+		},
+		run: func(args []string) error { return doubletsCmd(os.Stdout, args[0], args[1:]) },
+	})
+	r.add(&command{
+		name: "landmarks",
+		args: []argSpec{number("k")},
+		help: "selects k landmarks per component to speed up searches; 0 disables",
+		run: func(args []string) error { return landmarksCmd(args[0]) },
+	})
+	r.add(&command{
+		name: "tiebreak",
+		args: []argSpec{choice(false, "first", "lex", "freq", "h", "random"), {name: "seed", kind: argText, optional: true}},
+		help: "chooses between equally short paths",
+		details: []string{
+			"first takes the first path found, lex the alphabetically smallest, freq the one using the most frequent words, and h prefers words far from the destination",
+			"random chooses at random, repeatably for seed",
+		},
+		run: func(args []string) error { return tieBreakCmd(args[0], args[1:]) },
+	})
+	r.add(&command{
+		name: "output",
+		args: []argSpec{choice(false, "auto", "plain", "brackets", "colour")},
+		help: "sets how ladders are printed; auto uses colour on a terminal",
+		run: func(args []string) error { return outputCmd(args[0]) },
+	})
+	r.add(&command{
+		name: "frequencies",
+		args: []argSpec{{name: "path", kind: argPath}},
+		help: "reads word frequencies, one word and count per line, for tiebreak freq",
+		run: func(args []string) error { return frequenciesCmd(args[0]) },
+	})
+	r.add(&command{
+		name: "neighbours",
+		args: []argSpec{word("A")},
+		help: "lists the words one step from A, with the letters each step changes",
+		run: func(args []string) error { return neighboursCmd(args[0]) },
+	})
+	r.add(&command{
+		name: "ball",
+		args: []argSpec{word("A"), number("r")},
+		help: "lists the words within r steps of A, grouped by distance",
+		run: func(args []string) error { return ballCmd(args[0], args[1]) },
+	})
+	r.add(&command{
+		name: "degree",
+		args: []argSpec{word("A")},
+		help: "compares the number of neighbours of A with other words of its length",
+		run: func(args []string) error { return degreeCmd(args[0]) },
+	})
+	r.add(&command{
+		name: "critical",
+		args: []argSpec{{name: "length", kind: argText, optional: true}},
+		help: "lists the words and edges whose removal disconnects other words, of one length or all",
+		details: []string{
+			"each word or bridge is shown with the number of words it cuts off from the largest piece left,",
+			"and the sizes of all the pieces, largest first",
+			"only disconnection is reported: a word whose removal lengthens ladders without breaking",
+			"any is not critical; central ranks words by how many shortest ladders pass through them",
+		},
+		run: func(args []string) error {
+			length := ""
+			if len(args) == 1 {
+				length = args[0]
+			}
+			return criticalCmd(os.Stdout, length)
+		},
+	})
+	r.add(&command{
+		name: "central",
+		args: []argSpec{
+			{name: "length", kind: argText, optional: true},
+			{name: "n", kind: argText, optional: true},
+			{name: "option", kind: argText, optional: true, repeated: true},
+		},
+		help: "ranks the n words most central to the largest component of a length, or of all",
+		details: []string{
+			"betweenness counts the shortest ladders between other words that pass through a word, and closeness is the reciprocal of its mean distance to the rest",
+			"components of over 2000 words are estimated by searching from 500 words chosen at random",
+			"samples=k searches from k words instead, or from every word with samples=all; seed=s chooses them differently",
+			"csv=file writes every word's centrality to file",
+		},
+		run: func(args []string) error { return centralCmd(os.Stdout, args) },
+	})
+	r.add(&command{
+		name: "lengths",
+		args: []argSpec{word("A|length"), {name: "option", kind: argText, optional: true, repeated: true}},
+		help: "shows how many steps the shortest ladders from A take, or those between every pair of words of a length",
+		details: []string{
+			"lengths of over 2000 words are estimated by searching from 500 words chosen at random",
+			"samples=k searches from k words instead, or from every word with samples=all; seed=s chooses them differently",
+			"csv=file writes the counts to file",
+		},
+		run: func(args []string) error { return lengthsCmd(os.Stdout, args[0], args[1:]) },
+	})
+	r.add(&command{
+		name: "export",
+		args: []argSpec{
+			choice(false, "dot", "graphml"),
+			{name: "file", kind: argPath},
+			{name: "selection", kind: argChoice, choices: []string{"bucket", "component", "ball", "ladder"}},
+			{name: "arg", kind: argWord, repeated: true},
+		},
+		help: "exports part of the graph for Graphviz or Gephi",
+		details: []string{
+			"the selection is one of:",
+			"bucket n exports all words of length n",
+			"component A exports all words reachable from A",
+			"ball A r exports all words within r steps of A",
+			"ladder A B exports the shortest path from A to B",
+		},
+		run: func(args []string) error { return exportCmd(args[0], args[1], args[2], args[3:]) },
+	})
+	r.add(&command{
+		name: "set",
+		args: []argSpec{{name: "option", kind: argChoice, choices: settingNames(), optional: true}, {name: "value", kind: argText, optional: true, repeated: true}},
+		help: "shows every option, or sets one",
+		details: []string{
+			"output and tiebreak work as the commands of the same name",
+			"engine chooses the search algorithm: astar, dijkstra or bfs, which counts steps and ignores cost and tiebreak",
+			"cost unit makes every step cost one, and cost moves costs transpositions two and anagrams three",
+			"stats on shows statistics after every search",
+			"csv on makes central and lengths print CSV instead of tables",
+			"stop on makes a script stop at the first command that fails",
+		},
+		run: setCmd,
+	})
+	r.add(&command{
+		name: "echo",
+		args: []argSpec{{name: "text", kind: argText, optional: true, repeated: true}},
+		help: "prints text, to annotate the output of a script",
+		run: func(args []string) error {
+			fmt.Println(strings.Join(args, " "))
+			return nil
+		},
+	})
+	r.add(&command{
+		name: "quit",
+		help: "quits the application",
+		run: func(args []string) error {
+			fmt.Print("Quitting.\n\n")
+			return errQuit
+		},
+	})
+	return r
+}
+
+// the scan command's names for each move
+var moveNames = map[string]dictionary.Move{
+	"sub": dictionary.MoveSubstitution,
+	"swap": dictionary.MoveTransposition,
+	"anagram": dictionary.MoveAnagram,
+}
+
+// parses the arguments of the scan and load commands: an optional path,
+// then optionally stems and moves=list; the dictionary scanned is kept
+// under name, and put in use
+func scanArgs(name string, args []string) error {
+	path, stemsOnly := "dict.txt", false
+	var moves dictionary.Move
+	if len(args) > 0 && args[0] != "stems" && !strings.HasPrefix(args[0], "moves=") {
+		path, args = args[0], args[1:]
+	}
+	for _, arg := range args {
+		switch {
+		case arg == "stems":
+			stemsOnly = true
+		case strings.HasPrefix(arg, "moves="):
+			for _, name := range strings.Split(strings.TrimPrefix(arg, "moves="), ",") {
+				move, ok := moveNames[name]
+				if !ok {
+					return fmt.Errorf("unknown move %s; please use sub, swap or anagram", name)
+				}
+				moves |= move
+			}
+		default:
+			return errUsage
 		}
-	default:
-		fmt.Println("Sorry, command not understood.\n")
 	}
-	commandLoop(reader)
+	loaded, err := scan(path, stemsOnly, moves)
+	if err != nil {
+		return err
+	}
+	dictionaries[name] = loaded
+	useDictionary(name)
+	return nil
 }
 
-func scan(path string) {
+// reads the dictionary at path, as a Hunspell dictionary if it ends in .dic,
+// showing progress on a terminal; Ctrl-C abandons it
+func loadPath(path string, stemsOnly bool, moves dictionary.Move) (*dictionary.Dictionary, error) {
 	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
 	defer file.Close()
+	opts := dictionary.Options{StemsOnly: stemsOnly, Moves: moves}
+	if strings.HasSuffix(path, ".dic") {
+		// the .aff file is optional; without it only stems are scanned
+		opts.Hunspell = true
+		affPath := strings.TrimSuffix(path, ".dic") + ".aff"
+		if aff, err := os.Open(affPath); err == nil {
+			defer aff.Close()
+			opts.Affix = aff
+		} else {
+			fmt.Printf("No affix file found at %s, scanning stems only.\n", affPath)
+		}
+	}
+	if info, err := file.Stat(); err == nil && isTerminal(os.Stdout) {
+		opts.Progress = progressLine(info.Size())
+	}
+	// Ctrl-C abandons loading, rather than quitting the application
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	loaded, err := dictionary.LoadContext(ctx, file, opts)
+	if opts.Progress != nil {
+		fmt.Print("\n")
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil, errors.New("scanning aborted; the previous dictionary is unchanged")
+	}
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, fmt.Errorf("reading %s failed: %w", path, err)
 	}
-	var count int
-	graph, count, err = grapher.ScanLinkCompress(file)
+	return loaded, nil
+}
+
+// scans the dictionary at path, then describes it
+func scan(path string, stemsOnly bool, moves dictionary.Move) (*dictionary.Dictionary, error) {
+	loaded, err := loadPath(path, stemsOnly, moves)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "reading input failed with error:\n", err)
-		return
+		return nil, err
 	}
-	fmt.Printf("Words scanned: %d\n", count)
+	graph := loaded.Graph()
+	fmt.Printf("Words scanned: %d\n", loaded.Len())
 	fmt.Printf("Sub-graph count: %d\n", len(graph))
 	for k, v := range graph {
 		fmt.Printf("Sub-graph[%d] length: %d\n", k, len(v))
 	}
-	if count < 100 {
+	if loaded.Len() < 100 {
 		//not suitable for large graphs:
 		fmt.Println("FULL GRAPH: ", graph)
 	}
 	fmt.Printf("\n")
+	return loaded, nil
 }
 
-func searchCmd(src string, dst string) {
-	if graph == nil {
-		fmt.Println("No graph to search. Please scan a dictionary before searching.\n\n")
-		return
+// the interval between redraws of the progress line
+const progressInterval = 100 * time.Millisecond
+
+// a dictionary.Progress callback which redraws a single line of progress
+// at most every progressInterval, and whenever a bucket is compressed;
+// size is the number of bytes to be read
+func progressLine(size int64) func(dictionary.Progress) {
+	var drawn time.Time
+	return func(p dictionary.Progress) {
+		finished := p.Buckets > 0 && p.BucketsCompressed == p.Buckets
+		if !finished && time.Since(drawn) < progressInterval {
+			return
+		}
+		drawn = time.Now()
+		fmt.Printf("\rRead %.1f of %.1f MB, %d words scanned, %d of %d lengths linked, %d compressed ",
+			float64(p.BytesRead)/1e6, float64(size)/1e6, p.WordsScanned,
+			p.BucketsLinked, p.Buckets, p.BucketsCompressed)
 	}
-	if len(src) != len(dst) {
-		fmt.Println("Source and destination words are of different lengths. This is outside the problem domain.\n\n")
-		return
+}
+
+func searchCmd(d *dictionary.Dictionary, src string, dst string, showStats bool) error {
+	if d == nil {
+		return errNoDictionary
 	}
-	srcSubGraph, ok := graph[len(src)]
-	if !ok {
-		fmt.Println("Source word not found in dictionary.\n\n")
-		return
+	// either side may be a comma-separated list of alternatives
+	srcs, dsts := strings.Split(src, ","), strings.Split(dst, ",")
+	opts := searchOptions
+	var stats search.Stats
+	if showStats {
+		opts.Stats = &stats
 	}
-	srcNode, ok := srcSubGraph[src]
-	if !ok {
-		fmt.Println("Source word not found in dictionary.\n\n")
-		return
+	ladder, err := d.SearchMulti(context.Background(), srcs, dsts, opts)
+	if showStats && !errors.Is(err, dictionary.ErrLengthMismatch) && !errors.Is(err, dictionary.ErrUnknownWord) {
+		defer printStats(stats)
 	}
-	dstSubGraph, ok := graph[len(dst)]
-	if !ok {
-		fmt.Println("Destination word not found in dictionary.\n\n")
-		return
+	path := ladder.Words
+	switch {
+	case errors.Is(err, dictionary.ErrLengthMismatch):
+		return errors.New("source and destination words are of different lengths, which is outside the problem domain")
+	case errors.Is(err, dictionary.ErrUnknownWord) && !containsAll(d, srcs):
+		return errors.New("source word not found in dictionary")
+	case errors.Is(err, dictionary.ErrUnknownWord):
+		return errors.New("destination word not found in dictionary")
+	case errors.Is(err, dictionary.ErrNoPath):
+		fmt.Printf("No path was found between %s and %s.\n\n", src, dst)
+		return nil
+	case err != nil:
+		return err
 	}
-	dstNode, ok := dstSubGraph[dst]
-	if !ok {
-		fmt.Println("Destination word not found in dictionary.\n\n")
-		return
+	fmt.Printf("The shortest path between %s and %s is %d transformations long.\n", path[0], path[len(path)-1], len(path)-1)
+	fmt.Printf("Full path:\n")
+	printLadder(os.Stdout, ladder, ladderOutput.resolve(os.Stdout))
+	fmt.Printf("\n")
+	return nil
+}
+
+func containsAll(d *dictionary.Dictionary, words []string) bool {
+	for _, word := range words {
+		if !d.Contains(word) {
+			return false
+		}
 	}
+	return true
+}
 
-	path, distance, found := search.Path(search.GraphNode(srcNode), search.GraphNode(dstNode))
-	if !found {
-		fmt.Printf("No path was found between %s and %s.\n\n", src, dst)
-		return
+func landmarksCmd(arg string) error {
+	if dict == nil {
+		return errNoDictionary
 	}
-	fmt.Printf("The shortest path between %s and %s is %d transformations long.\n", src, dst, distance)
-	fmt.Printf("Full path:\n")
-	for k, v := range path {
-		fmt.Printf("%d: %s\n", k, v.(*grapher.WordNode).Word)
+	perComponent, err := strconv.Atoi(arg)
+	if err != nil || perComponent < 0 {
+		return fmt.Errorf("%s is not a landmark count", arg)
 	}
-	fmt.Printf("\n")
+	dict.UseLandmarks(perComponent)
+	if perComponent == 0 {
+		fmt.Print("Landmarks disabled.\n\n")
+		return nil
+	}
+	fmt.Printf("Selected up to %d landmarks per component.\n\n", perComponent)
+	return nil
 }
 
+func tieBreakCmd(mode string, args []string) error {
+	tieBreak, ok := tieBreaks[mode]
+	if !ok {
+		return fmt.Errorf("unknown tiebreak mode %s; please use first, lex, freq, h or random", mode)
+	}
+	var seed int64
+	if len(args) == 1 {
+		var err error
+		if seed, err = strconv.ParseInt(args[0], 10, 64); err != nil || tieBreak != search.TieRandom {
+			return errors.New("only the random mode takes a seed, which must be a whole number")
+		}
+	}
+	searchOptions.TieBreak, searchOptions.Seed = tieBreak, seed
+	fmt.Printf("Ties between equally short paths will be broken by %s.\n\n", mode)
+	return nil
+}
 
+func outputCmd(name string) error {
+	style, ok := ladderStyles[name]
+	if !ok {
+		return fmt.Errorf("unknown output style %s; please use auto, plain, brackets or colour", name)
+	}
+	ladderOutput = style
+	fmt.Printf("Ladders will be printed in the %s style.\n\n", name)
+	return nil
+}
 
+func frequenciesCmd(path string) error {
+	if dict == nil {
+		return errNoDictionary
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err = dict.LoadFrequencies(file); err != nil {
+		return fmt.Errorf("reading frequencies failed: %w", err)
+	}
+	fmt.Printf("Word frequencies read from %s.\n\n", path)
+	return nil
+}
 
+func printStats(stats search.Stats) {
+	fmt.Printf("Search statistics:\n")
+	fmt.Printf("Nodes expanded: %d\n", stats.Expanded)
+	fmt.Printf("Nodes generated: %d\n", stats.Generated)
+	fmt.Printf("Nodes reopened: %d\n", stats.Reopened)
+	fmt.Printf("Peak queue size: %d\n", stats.PeakQueue)
+	fmt.Printf("Deepest expansion: %d\n", stats.Depth)
+	fmt.Printf("Time taken: %v\n\n", stats.Elapsed)
+}
 
+// nil if the word is not in the graph
+func lookup(word string) *grapher.WordNode {
+	return dict.Graph()[len(word)][word]
+}
 
+// the node for word, or an error explaining why there is none
+func exploreNode(word string) (*grapher.WordNode, error) {
+	if dict == nil {
+		return nil, errNoDictionary
+	}
+	node := lookup(word)
+	if node == nil {
+		return nil, fmt.Errorf("%s not found in dictionary", word)
+	}
+	return node, nil
+}
 
+func neighboursCmd(word string) error {
+	node, err := exploreNode(word)
+	if err != nil {
+		return err
+	}
+	neighbours := node.SortedNeighbours()
+	if len(neighbours) == 0 {
+		fmt.Printf("%s has no neighbours.\n\n", word)
+		return nil
+	}
+	fmt.Printf("%s has %d neighbours:\n", word, len(neighbours))
+	for _, n := range neighbours {
+		move := node.NeighbourMove(n)
+		fmt.Printf("%s\t%v\t%s\n", n.Word, move, describeChange(word, n.Word, move))
+	}
+	fmt.Print("\n")
+	return nil
+}
 
+func ballCmd(word string, arg string) error {
+	radius, err := strconv.Atoi(arg)
+	if err != nil || radius < 0 {
+		return fmt.Errorf("%s is not a radius of zero or more", arg)
+	}
+	node, err := exploreNode(word)
+	if err != nil {
+		return err
+	}
+	shells := grapher.Shells(node, radius)
+	total := 0
+	for distance, shell := range shells {
+		total += len(shell)
+		fmt.Printf("%d (%d): %s\n", distance, len(shell), strings.Join(wordsOf(shell), " "))
+	}
+	if len(shells) <= radius {
+		fmt.Printf("No words are more than %d steps from %s.\n", len(shells)-1, word)
+	}
+	fmt.Printf("%d words within %d steps of %s.\n\n", total, radius, word)
+	return nil
+}
 
+func degreeCmd(word string) error {
+	node, err := exploreNode(word)
+	if err != nil {
+		return err
+	}
+	degrees := dict.Graph().Degrees(len(word))
+	fmt.Printf("%s has %d neighbours.\n", word, node.Degree())
+	fmt.Printf("Words of length %d have %.1f on average and at most %d; %d of %d have none.\n",
+		len(word), degrees.Mean, degrees.Max, degrees.Isolated, degrees.Words)
+	fmt.Printf("%d words, including %s, can be reached from %s.\n\n", len(grapher.Component(node)), word, word)
+	return nil
+}
 
+func wordsOf(nodes []*grapher.WordNode) []string {
+	words := make([]string, len(nodes))
+	for i, node := range nodes {
+		words[i] = node.Word
+	}
+	return words
+}
 
+func exportCmd(format string, path string, selection string, args []string) error {
+	if dict == nil {
+		return errNoDictionary
+	}
+	write := grapher.WriteDOT
+	switch format {
+	case "dot":
+	case "graphml":
+		write = grapher.WriteGraphML
+	default:
+		return fmt.Errorf("unknown export format %s; please use dot or graphml", format)
+	}
+	export := grapher.Export{Name: selection + " " + strings.Join(args, " ")}
+	switch {
+	case selection == "bucket" && len(args) == 1:
+		letterCount, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("%s is not a word length", args[0])
+		}
+		export.Nodes = dict.Graph().Bucket(letterCount)
+	case selection == "component" && len(args) == 1:
+		node := lookup(args[0])
+		if node == nil {
+			return fmt.Errorf("%s not found in dictionary", args[0])
+		}
+		export.Nodes = grapher.Component(node)
+	case selection == "ball" && len(args) == 2:
+		node := lookup(args[0])
+		radius, err := strconv.Atoi(args[1])
+		if node == nil || err != nil || radius < 0 {
+			return errors.New("the ball selection requires a word in the dictionary and a radius of zero or more")
+		}
+		export.Nodes = grapher.Neighbourhood(node, radius)
+	case selection == "ladder" && len(args) == 2:
+		var err error
+		if export.Ladder, err = exportLadder(args[0], args[1]); err != nil {
+			return err
+		}
+		export.Nodes = export.Ladder
+	default:
+		return errors.New("please select one of: bucket n, component A, ball A r, ladder A B")
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err = write(file, export); err != nil {
+		return fmt.Errorf("writing export failed: %w", err)
+	}
+	fmt.Printf("Exported %d words to %s.\n\n", len(export.Nodes), path)
+	return nil
+}
 
+// the words of the ladder from src to dst, searched as by the search command,
+// with the dictionary's moves, landmarks and tie-break, so that the ladder
+// exported is the one search prints
+func exportLadder(src string, dst string) ([]*grapher.WordNode, error) {
+	ladder, err := dict.Search(context.Background(), src, dst, searchOptions)
+	if errors.Is(err, dictionary.ErrNoPath) {
+		return nil, fmt.Errorf("no path was found between %s and %s", src, dst)
+	}
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*grapher.WordNode, len(ladder.Words))
+	for i, word := range ladder.Words {
+		nodes[i] = lookup(word)
+	}
+	return nodes, nil
+}
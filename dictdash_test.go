@@ -4,6 +4,11 @@ package main
 import (
 		"testing"
 		"os"
+		"strings"
+		"context"
+		"errors"
+		"path/filepath"
+		"github.com/nerophon/dictdash/dictionary"
 		"github.com/nerophon/dictdash/grapher"
 		"github.com/nerophon/dictdash/search"
 )
@@ -17,6 +22,86 @@ func TestSanity(t *testing.T) {
 	}
 }
 
+func TestPrintLadder(t *testing.T) {
+	ladder := dictionary.Ladder{
+		Words: []string{"cold", "clod", "clad", "dlac"},
+		Moves: []dictionary.Move{dictionary.MoveTransposition, dictionary.MoveSubstitution, dictionary.MoveAnagram},
+	}
+	cases := []struct {
+		style ladderStyle
+		want string
+	}{
+		{stylePlain, "0: cold\n1: clod (transposition)\n2: clad\n3: dlac (anagram)\n"},
+		{styleBrackets, "" +
+			"step  word      move           change\n" +
+			"   0  cold\n" +
+			"   1  c[lo]d    transposition  ol → lo\n" +
+			"   2  cl[a]d    substitution   o → a\n" +
+			"   3  [d]la[c]  anagram        clad → dlac\n"},
+		{styleColour, "" +
+			"step  word  move           change\n" +
+			"   0  cold\n" +
+			"   1  c\x1b[1;33mlo\x1b[0md  transposition  ol → lo\n" +
+			"   2  cl\x1b[1;33ma\x1b[0md  substitution   o → a\n" +
+			"   3  \x1b[1;33md\x1b[0mla\x1b[1;33mc\x1b[0m  anagram        clad → dlac\n"},
+	}
+	for _, c := range cases {
+		var b strings.Builder
+		printLadder(&b, ladder, c.style)
+		if got := b.String(); got != c.want {
+			t.Errorf("printLadder(%d), want=\n%s\ngot=\n%s", c.style, c.want, got)
+		}
+	}
+	if stylePlain.resolve(os.Stdout) != stylePlain {
+		t.Errorf("resolve(), want an explicit style kept")
+	}
+}
+
+func TestExportCmd(t *testing.T) {
+	defer func(saved *dictionary.Dictionary) { dict = saved }(dict)
+	var err error
+	dict, err = dictionary.Load(strings.NewReader("cold cord card ward warm cat zzzz"), dictionary.Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	path := filepath.Join(t.TempDir(), "ladder.dot")
+	if err = exportCmd("dot", path, "ladder", []string{"cold", "warm"}); err != nil {
+		t.Fatalf("exportCmd(ladder cold warm), unexpected error=%v", err)
+	}
+	cases := []struct {
+		src, dst string
+		err error
+	}{
+		{"cold", "cat", dictionary.ErrLengthMismatch},
+		{"cold", "cole", dictionary.ErrUnknownWord},
+	}
+	for _, c := range cases {
+		if err = exportCmd("dot", path, "ladder", []string{c.src, c.dst}); !errors.Is(err, c.err) {
+			t.Errorf("exportCmd(ladder %s %s), want %v, got %v", c.src, c.dst, c.err, err)
+		}
+	}
+	if err = exportCmd("dot", path, "ladder", []string{"cold", "zzzz"}); err == nil {
+		t.Errorf("exportCmd(ladder cold zzzz), want error")
+	}
+}
+
+func TestExportLadderMoves(t *testing.T) {
+	defer func(saved *dictionary.Dictionary) { dict = saved }(dict)
+	var err error
+	dict, err = dictionary.Load(strings.NewReader("cold clod clad cord card"), dictionary.Options{Moves: dictionary.MoveSubstitution | dictionary.MoveTransposition})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	nodes, err := exportLadder("cold", "clad")
+	if err != nil {
+		t.Fatalf("exportLadder(cold, clad), unexpected error=%v", err)
+	}
+	ladder, _ := dict.Search(context.Background(), "cold", "clad", searchOptions)
+	if got, want := strings.Join(wordsOf(nodes), " "), strings.Join(ladder.Words, " "); got != want || want != "cold clod clad" {
+		t.Errorf("exportLadder(cold, clad), want=%q as searched, got=%q", want, got)
+	}
+}
+
 func BenchmarkOpen(b *testing.B) {
     for n := 0; n < b.N; n++ {
 		file, _ := os.Open("dict.txt")
@@ -41,27 +126,72 @@ func BenchmarkSearch(b *testing.B) {
 	graphRes, _, _ := grapher.ScanLinkCompress(file)
 	benchGraph = graphRes // to prevent compiler skip
 
-	srcNode, _ := graph[6]["bounce"]
-	dstNode, _ := graph[6]["lather"]
+	srcNode, _ := graphRes[6]["bounce"]
+	dstNode, _ := graphRes[6]["lather"]
 	src := search.GraphNode(srcNode)
 	dst := search.GraphNode(dstNode)
+	b.ReportAllocs()
 	b.ResetTimer()
 
     // run the Search b.N times
     for n := 0; n < b.N; n++ {
-		_, _, found := search.Path(src, dst)
-		benchFound = found // to prevent compiler skip
+		result := search.PathContext(context.Background(), src, dst, search.Options{})
+		benchFound = result.Outcome == search.Found // to prevent compiler skip
     }
+	b.StopTimer()
+	var stats search.Stats
+	search.PathContext(context.Background(), src, dst, search.Options{Stats: &stats})
+	reportStats(b, stats)
 }
 
+// as BenchmarkSearch, but through a Dictionary, which reuses
+// pooled search workspaces; compare allocs/op with BenchmarkSearch
+func BenchmarkSearchDictionary(b *testing.B) {
+	// setup
+	file, _ := os.Open("dict.txt")
+	defer file.Close()
+	d, _ := dictionary.Load(file, dictionary.Options{})
+	var ladder dictionary.Ladder
+	b.ReportAllocs()
+	b.ResetTimer()
 
+	for n := 0; n < b.N; n++ {
+		ladder, _ = d.Search(context.Background(), "bounce", "lather", search.Options{})
+		benchFound = len(ladder.Words) > 0 // to prevent compiler skip
+	}
+	reportSearchStats(b, d)
+}
 
+func BenchmarkSearchLandmarks(b *testing.B) {
+	// setup
+	file, _ := os.Open("dict.txt")
+	defer file.Close()
+	d, _ := dictionary.Load(file, dictionary.Options{Landmarks: 16})
+	var ladder dictionary.Ladder
+	b.ReportAllocs()
+	b.ResetTimer()
 
+	// compare expanded/op with BenchmarkSearch
+	for n := 0; n < b.N; n++ {
+		ladder, _ = d.Search(context.Background(), "bounce", "lather", search.Options{})
+		benchFound = len(ladder.Words) > 0 // to prevent compiler skip
+	}
+	reportSearchStats(b, d)
+}
 
+// as reportStats, for one more search of d outside the timings,
+// so that the timed searches skip gathering statistics
+func reportSearchStats(b *testing.B, d *dictionary.Dictionary) {
+	b.StopTimer()
+	var stats search.Stats
+	d.Search(context.Background(), "bounce", "lather", search.Options{Stats: &stats})
+	reportStats(b, stats)
+}
 
-
-
-
-
-
-
+// reports search statistics alongside the timings,
+// so that heuristic and engine changes can be compared
+func reportStats(b *testing.B, stats search.Stats) {
+	b.ReportMetric(float64(stats.Expanded), "expanded/op")
+	b.ReportMetric(float64(stats.Generated), "generated/op")
+	b.ReportMetric(float64(stats.PeakQueue), "peakqueue/op")
+}
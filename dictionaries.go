@@ -0,0 +1,161 @@
+package main
+
+import (
+		"io"
+		"fmt"
+		"sort"
+		"errors"
+		"context"
+		"strings"
+		"text/tabwriter"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+// several dictionaries may be loaded at once, each under a name;
+// commands work on the one in use, dict, except where a name is given
+
+// the loaded dictionaries, by name
+var dictionaries = make(map[string]*dictionary.Dictionary)
+
+// the name of dict, or empty if no dictionary is in use
+var dictName string
+
+// the name under which scan keeps the dictionary it loads:
+// the one in use, which it replaces, or "default"
+func scanName() string {
+	if dictName == "" {
+		return "default"
+	}
+	return dictName
+}
+
+// panics if no dictionary is loaded under name
+func useDictionary(name string) {
+	d := dictionaries[name]
+	if d == nil {
+		panic("dictdash: no dictionary named " + name)
+	}
+	dict, dictName = d, name
+}
+
+func namedDictionary(name string) (*dictionary.Dictionary, error) {
+	if d := dictionaries[name]; d != nil {
+		return d, nil
+	}
+	if len(dictionaries) == 0 {
+		return nil, errNoDictionary
+	}
+	return nil, fmt.Errorf("there is no dictionary named %s; please use %s", name, strings.Join(dictionaryNames(), ", "))
+}
+
+// the names of the loaded dictionaries in alphabetical order
+func dictionaryNames() []string {
+	names := make([]string, 0, len(dictionaries))
+	for name := range dictionaries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writes each loaded dictionary's name and size, marking the one in use
+func listDictionaries(w io.Writer) {
+	if len(dictionaries) == 0 {
+		fmt.Fprint(w, "No dictionaries are loaded.\n\n")
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, name := range dictionaryNames() {
+		mark := " "
+		if name == dictName {
+			mark = "*"
+		}
+		fmt.Fprintf(tw, "%s %s\t%d words\n", mark, name, dictionaries[name].Len())
+	}
+	tw.Flush()
+	fmt.Fprint(w, "\n")
+}
+
+func unloadDictionary(name string) error {
+	if _, err := namedDictionary(name); err != nil {
+		return err
+	}
+	delete(dictionaries, name)
+	if name == dictName {
+		dict, dictName = nil, ""
+		fmt.Printf("Unloaded %s, which was in use; please use another dictionary.\n\n", name)
+		return nil
+	}
+	fmt.Printf("Unloaded %s.\n\n", name)
+	return nil
+}
+
+// writes a table of the shortest ladders from src to dst in the named
+// dictionaries, or all of them if names is empty, followed by a summary
+// of how they differ
+func compareCmd(w io.Writer, src string, dst string, names []string) error {
+	if len(dictionaries) == 0 {
+		return errNoDictionary
+	}
+	if len(names) == 0 {
+		names = dictionaryNames()
+	}
+	srcs, dsts := strings.Split(src, ","), strings.Split(dst, ",")
+	var found, missing []string // the names of the dictionaries with and without a ladder
+	steps := make(map[string]int)
+	shortest := -1
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprint(tw, "dictionary\tsteps\tladder\n")
+	for _, name := range names {
+		d, err := namedDictionary(name)
+		if err != nil {
+			return err
+		}
+		ladder, err := d.SearchMulti(context.Background(), srcs, dsts, searchOptions)
+		switch {
+		case err == nil:
+			steps[name] = len(ladder.Words) - 1
+			fmt.Fprintf(tw, "%s\t%d\t%s\n", name, steps[name], strings.Join(ladder.Words, " "))
+			found = append(found, name)
+			if shortest < 0 || steps[name] < shortest {
+				shortest = steps[name]
+			}
+			continue
+		case errors.Is(err, dictionary.ErrLengthMismatch):
+			return errors.New("source and destination words are of different lengths, which is outside the problem domain")
+		case errors.Is(err, dictionary.ErrUnknownWord):
+			var unknown []string
+			for _, word := range append(srcs, dsts...) {
+				if !d.Contains(word) {
+					unknown = append(unknown, word)
+				}
+			}
+			fmt.Fprintf(tw, "%s\t-\tnot in dictionary: %s\n", name, strings.Join(unknown, " "))
+		case errors.Is(err, dictionary.ErrNoPath):
+			fmt.Fprintf(tw, "%s\t-\tno path\n", name)
+		default:
+			return err
+		}
+		missing = append(missing, name)
+	}
+	tw.Flush()
+	switch {
+	case len(found) == 0:
+		fmt.Fprint(w, "There is no ladder in any of these dictionaries.\n\n")
+		return nil
+	case len(missing) > 0:
+		fmt.Fprintf(w, "A ladder exists in %s, but not in %s.\n", strings.Join(found, ", "), strings.Join(missing, ", "))
+	}
+	var shortestIn []string
+	for _, name := range found {
+		if steps[name] == shortest {
+			shortestIn = append(shortestIn, name)
+		}
+	}
+	if len(shortestIn) == len(found) {
+		fmt.Fprintf(w, "The shortest ladder is %d steps long wherever one exists.\n\n", shortest)
+		return nil
+	}
+	fmt.Fprintf(w, "The shortest ladder, of %d steps, is in %s.\n\n", shortest, strings.Join(shortestIn, ", "))
+	return nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+		"testing"
+		"os"
+		"bytes"
+		"strings"
+		"path/filepath"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestDictionaries(t *testing.T) {
+	defer func(saved map[string]*dictionary.Dictionary, name string, d *dictionary.Dictionary) {
+		dictionaries, dictName, dict = saved, name, d
+	}(dictionaries, dictName, dict)
+	dictionaries, dictName, dict = make(map[string]*dictionary.Dictionary), "", nil
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"common.txt": "cold bold bald bard ward warm",
+		"big.txt": "cold cord card ward warm cards",
+		"small.txt": "cold warm",
+	}
+	for name, words := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(words), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := newCommands()
+	steps := []struct {
+		line string
+		err string
+		use string // the dictionary in use afterwards
+	}{
+		{"use common", "there is no dictionary; please scan one first", ""},
+		{"load common " + filepath.Join(dir, "common.txt"), "", "common"},
+		{"load big " + filepath.Join(dir, "big.txt"), "", "big"},
+		{"use common", "", "common"},
+		{"use other", "there is no dictionary named other; please use big, common", "common"},
+		{"search cold warm big", "", "common"},
+		{"search --stats cold warm other", "there is no dictionary named other; please use big, common", "common"},
+		{"scan " + filepath.Join(dir, "small.txt"), "", "common"},
+		{"unload common", "", ""},
+		{"scan " + filepath.Join(dir, "small.txt"), "", "default"},
+	}
+	for _, c := range steps {
+		got := ""
+		if err := r.execute(c.line); err != nil {
+			got = err.Error()
+		}
+		if got != c.err || dictName != c.use {
+			t.Errorf("execute(%q), want=%q using %q, got=%q using %q", c.line, c.err, c.use, got, dictName)
+		}
+	}
+	if got := strings.Join(dictionaryNames(), " "); got != "big default" {
+		t.Errorf("dictionaryNames(), want=%q, got=%q", "big default", got)
+	}
+	r.execute("load common " + filepath.Join(dir, "common.txt"))
+	r.execute("load empty " + filepath.Join(dir, "small.txt"))
+	r.execute("use default")
+
+	var out bytes.Buffer
+	if err := compareCmd(&out, "cold", "warm", nil); err != nil {
+		t.Fatalf("compareCmd(), unexpected error=%v", err)
+	}
+	want := "dictionary  steps  ladder\n" +
+		"big         4      cold cord card ward warm\n" +
+		"common      5      cold bold bald bard ward warm\n" +
+		"default     -      no path\n" +
+		"empty       -      no path\n" +
+		"A ladder exists in big, common, but not in default, empty.\n" +
+		"The shortest ladder, of 4 steps, is in big.\n\n"
+	if out.String() != want {
+		t.Errorf("compareCmd(), want:\n%s\ngot:\n%s", want, out.String())
+	}
+	out.Reset()
+	compareCmd(&out, "ward", "warm", []string{"common", "big"})
+	if want := "The shortest ladder is 1 steps long wherever one exists.\n\n"; !strings.HasSuffix(out.String(), want) {
+		t.Errorf("compareCmd(common, big), want suffix %q, got:\n%s", want, out.String())
+	}
+	out.Reset()
+	compareCmd(&out, "cord", "card", []string{"common"})
+	if want := "common      -      not in dictionary: cord card\nThere is no ladder"; !strings.Contains(out.String(), want) {
+		t.Errorf("compareCmd(common), want %q in:\n%s", want, out.String())
+	}
+	if err := compareCmd(&out, "cold", "cards", nil); err == nil {
+		t.Errorf("compareCmd(cold, cards), want error")
+	}
+}
@@ -0,0 +1,113 @@
+package dictionary
+
+import (
+		"sync"
+		"container/list"
+		"github.com/nerophon/dictdash/search"
+)
+
+// identifies a ladder query; the version changes whenever the dictionary
+// does, so that entries from before a change can never be returned
+type cacheKey struct {
+	version uint64
+	srcs, dsts string // comma-joined
+	opts search.Options
+}
+
+// the reverse of the query, which has the reversed answer,
+// since every transformation can be undone
+func (key cacheKey) reversed() cacheKey {
+	key.srcs, key.dsts = key.dsts, key.srcs
+	return key
+}
+
+type cacheEntry struct {
+	key cacheKey
+	words []string // nil if there is no path
+}
+
+// a concurrency-safe least-recently-used cache of ladders
+type cache struct {
+	mu sync.Mutex
+	capacity int
+	order *list.List // of *cacheEntry, most recently used first
+	entries map[cacheKey]*list.Element
+}
+
+func newCache(capacity int) *cache {
+	return &cache{
+		capacity: capacity,
+		order: list.New(),
+		entries: make(map[cacheKey]*list.Element),
+	}
+}
+
+// looks up key, then its reverse where that gives the same answer;
+// the returned words must not be modified
+func (c *cache) get(key cacheKey) (words []string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.order.MoveToFront(e)
+		return e.Value.(*cacheEntry).words, true
+	}
+	if e, ok := c.entries[key.reversed()]; ok && reversible(e.Value.(*cacheEntry).words) {
+		c.order.MoveToFront(e)
+		return reverse(e.Value.(*cacheEntry).words), true
+	}
+	return nil, false
+}
+
+// reports whether the reverse of words answers the reversed query: the
+// absence of a ladder and ladders of one step always do, being the only
+// answers possible, but otherwise every tie-break may choose a different
+// ladder of the same length in the other direction (TieFirst by the order
+// of neighbours, and the others by comparing from the other end), and
+// serving the reverse would make a query's answer depend on whether its
+// reverse was asked first
+func reversible(words []string) bool {
+	return len(words) <= 2
+}
+
+// stores words under key, evicting the least recently used entry if full
+func (c *cache) put(key cacheKey, words []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*cacheEntry).words = words
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key, words})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// removes every entry
+func (c *cache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	c.entries = make(map[cacheKey]*list.Element)
+}
+
+func (c *cache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// a reversed copy of words; nil stays nil
+func reverse(words []string) []string {
+	if words == nil {
+		return nil
+	}
+	reversed := make([]string, len(words))
+	for i, word := range words {
+		reversed[len(words)-1-i] = word
+	}
+	return reversed
+}
@@ -0,0 +1,147 @@
+package dictionary
+
+import (
+		"testing"
+		"reflect"
+		"strings"
+		"sync"
+		"errors"
+		"context"
+		"github.com/nerophon/dictdash/search"
+)
+
+func TestCache(t *testing.T) {
+	c := newCache(2)
+	ab := cacheKey{srcs: "a", dsts: "b"}
+	cd := cacheKey{srcs: "c", dsts: "d"}
+	ef := cacheKey{srcs: "e", dsts: "f"}
+	c.put(ab, []string{"a", "x", "b"})
+	c.put(cd, nil)
+	cases := []struct {
+		key cacheKey
+		want []string
+		wantOK bool
+	}{
+		{ab, []string{"a", "x", "b"}, true},
+		{ab.reversed(), nil, false}, // another ladder may be preferred that way
+		{cd, nil, true},
+		{cd.reversed(), nil, true},
+		{ef, nil, false},
+		{cacheKey{version: 1, srcs: "a", dsts: "b"}, nil, false},
+		{cacheKey{srcs: "a", dsts: "b", opts: search.Options{MaxDepth: 2}}, nil, false},
+	}
+	for _, c2 := range cases {
+		got, ok := c.get(c2.key)
+		if ok != c2.wantOK || !reflect.DeepEqual(got, c2.want) {
+			t.Errorf("get(%+v), want=%v,%t, got=%v,%t", c2.key, c2.want, c2.wantOK, got, ok)
+		}
+	}
+	// cd was used last, so ab is evicted
+	c.put(ef, []string{"e", "f"})
+	if _, ok := c.get(ab); ok {
+		t.Errorf("put(), want least recently used entry evicted")
+	}
+	if _, ok := c.get(cd); !ok || c.len() != 2 {
+		t.Errorf("put(), want recently used entry kept, len=%d", c.len())
+	}
+	c.purge()
+	if c.len() != 0 {
+		t.Errorf("purge(), want empty cache, len=%d", c.len())
+	}
+	// a ladder of one step is the only one, so it answers the reverse too
+	c.put(ab, []string{"a", "b"})
+	if got, ok := c.get(ab.reversed()); !ok || !reflect.DeepEqual(got, []string{"b", "a"}) {
+		t.Errorf("get(reversed one step), want=[b a],true, got=%v,%t", got, ok)
+	}
+}
+
+func TestCachedSearch(t *testing.T) {
+	d, _ := Load(strings.NewReader("cold cord card ward warm zzzz"), Options{CacheSize: 8})
+	ctx := context.Background()
+	first, err := d.Search(ctx, "cold", "warm", search.Options{})
+	if err != nil || first.Cached {
+		t.Fatalf("Search(), want uncached result, got=%+v, err=%v", first, err)
+	}
+	reverse, err := d.Search(ctx, "warm", "cold", search.Options{})
+	if err != nil || reverse.Cached || !reflect.DeepEqual(reverse.Words, []string{"warm", "ward", "card", "cord", "cold"}) {
+		t.Errorf("Search(reverse), want uncached reversed ladder, got=%+v, err=%v", reverse, err)
+	}
+	// asking for statistics does not stop the ladder being served from the cache
+	stats := search.Stats{Expanded: 1}
+	if withStats, _ := d.Search(ctx, "cold", "warm", search.Options{Stats: &stats}); !withStats.Cached || stats != (search.Stats{}) {
+		t.Errorf("Search(stats), want cached ladder and no statistics, got=%+v, stats=%+v", withStats, stats)
+	}
+	d.Search(ctx, "zzzz", "cold", search.Options{})
+	noPath, err := d.Search(ctx, "cold", "zzzz", search.Options{})
+	if !errors.Is(err, ErrNoPath) || !noPath.Cached {
+		t.Errorf("Search(no path), want cached ErrNoPath, got=%+v, err=%v", noPath, err)
+	}
+	// a change to the dictionary invalidates every ladder
+	if added, err := d.Add("wold", "word"); added != 2 || err != nil {
+		t.Fatalf("Add(), want 2 added, got=%d, err=%v", added, err)
+	}
+	if d.cache.len() != 0 {
+		t.Errorf("Add(), want cache purged, len=%d", d.cache.len())
+	}
+	again, err := d.Search(ctx, "cold", "warm", search.Options{})
+	if err != nil || again.Cached {
+		t.Errorf("Search() after Add, want uncached result, got=%+v, err=%v", again, err)
+	}
+	if removed := d.Remove("cord", "nope"); removed != 1 || d.Len() != 7 {
+		t.Errorf("Remove(), want 1 removed and 7 left, got=%d, len=%d", removed, d.Len())
+	}
+	after, err := d.Search(ctx, "cold", "warm", search.Options{})
+	if err != nil || after.Cached || after.Words[1] != "wold" {
+		t.Errorf("Search() after Remove, want a ladder avoiding cord, got=%+v, err=%v", after, err)
+	}
+	if _, err := d.Add("ok", "Not"); !errors.Is(err, ErrInvalidWord) || d.Contains("ok") {
+		t.Errorf("Add(invalid), want ErrInvalidWord and nothing added, err=%v", err)
+	}
+}
+
+// a tie-break may choose differently in each direction, so the ladder
+// returned must not depend on whether the reverse was searched first
+func TestCachedSearchTieBreak(t *testing.T) {
+	ctx := context.Background()
+	for _, tieBreak := range []search.TieBreak{search.TieFirst, search.TieLexicographic, search.TieFrequency} {
+		opts := search.Options{TieBreak: tieBreak}
+		fresh, _ := Load(strings.NewReader("cat cot dot dog dat dag"), Options{CacheSize: 8})
+		want, err := fresh.Search(ctx, "dog", "cat", opts)
+		if err != nil || len(want.Words) != 4 {
+			t.Fatalf("Search(dog, cat, %+v), want a ladder of 3 steps, got=%v, err=%v", opts, want.Words, err)
+		}
+		if tieBreak == search.TieLexicographic && strings.Join(want.Words, " ") != "dog dag dat cat" {
+			t.Errorf("Search(dog, cat, %+v), want dog dag dat cat, got=%v", opts, want.Words)
+		}
+		d, _ := Load(strings.NewReader("cat cot dot dog dat dag"), Options{CacheSize: 8})
+		d.Search(ctx, "cat", "dog", opts)
+		got, err := d.Search(ctx, "dog", "cat", opts)
+		if err != nil || got.Cached || !reflect.DeepEqual(got.Words, want.Words) {
+			t.Errorf("Search(dog, cat, %+v) after cat to dog, want uncached %v, got=%+v, err=%v", opts, want.Words, got, err)
+		}
+		if again, _ := d.Search(ctx, "dog", "cat", opts); !again.Cached || !reflect.DeepEqual(again.Words, want.Words) {
+			t.Errorf("Search(dog, cat, %+v) again, want cached %v, got=%+v", opts, want.Words, again)
+		}
+	}
+}
+
+func TestConcurrentCachedSearch(t *testing.T) {
+	d, _ := Load(strings.NewReader("cold cord card ward warm wold word"), Options{CacheSize: 2})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if j%8 == i {
+					d.Add("bold")
+					d.Remove("bold")
+				}
+				if _, err := d.ShortestPath(context.Background(), "cold", "warm"); err != nil {
+					t.Errorf("ShortestPath(), unexpected error=%v", err)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,425 @@
+/*
+Package dictionary is the library interface to dictdash:
+it loads a word list into a graph and answers string-level queries about it,
+without callers needing to know about grapher or search.
+*/
+package dictionary
+
+import (
+		"io"
+		"fmt"
+		"errors"
+		"sort"
+		"strings"
+		"sync"
+		"context"
+		"github.com/nerophon/dictdash/grapher"
+		"github.com/nerophon/dictdash/search"
+)
+
+// errors returned by Dictionary queries, usable with errors.Is;
+// the returned errors wrap these with the offending word(s)
+var (
+	ErrUnknownWord = errors.New("unknown word")
+	ErrLengthMismatch = errors.New("words are of different lengths")
+	ErrNoPath = errors.New("no path")
+	ErrInvalidWord = errors.New("words may only contain the letters a to z")
+)
+
+// options for loading a Dictionary
+type Options struct {
+	// when true, the input is read as a Hunspell .dic file
+	Hunspell bool
+	// the Hunspell .aff file used to expand stems; may be nil
+	Affix io.Reader
+	// when true, Hunspell affix rules are not applied
+	StemsOnly bool
+	// the number of landmarks to select per component for the ALT heuristic;
+	// zero uses the Hamming distance alone
+	Landmarks int
+	// the number of ladders to remember, so that repeated queries
+	// (in either direction) need not be searched again; zero disables caching
+	CacheSize int
+	// word frequencies for search.TieFrequency, one word and count per line;
+	// may be nil, in which case every word is equally frequent
+	Frequencies io.Reader
+	// if set, called as loading progresses, from one goroutine at a time
+	Progress func(Progress)
+	// the kinds of step a ladder may take; zero allows substitutions only
+	Moves Move
+}
+
+// the kinds of step a ladder may take, combined with |
+type Move = grapher.Move
+
+const (
+	MoveSubstitution = grapher.MoveSubstitution
+	MoveTransposition = grapher.MoveTransposition
+	MoveAnagram = grapher.MoveAnagram
+)
+
+// how far loading has got: bytes read, distinct words scanned,
+// and word lengths linked and compressed
+type Progress = grapher.Progress
+
+// a word list linked into a graph of single-letter transformations;
+// safe for concurrent use
+type Dictionary struct {
+	mu sync.RWMutex // guards everything below; held for reading during queries
+	graph grapher.WordGraph
+	count int
+	landmarks *grapher.Landmarks
+	index *grapher.Index // node IDs, so that searches can use dense arrays
+	frequencies map[string]float64
+	moves Move
+	version uint64 // incremented on every change affecting query results
+	cache *cache // nil if caching is disabled
+	workspaces sync.Pool // of *search.Workspace, reused between searches
+}
+
+// a graph whose nodes are numbered by an Index, and which supports
+// every search.TieBreak
+type wordGraph struct {
+	search.Graph[search.GraphNode, int]
+	*grapher.Index
+	frequencies map[string]float64
+}
+
+// orders words alphabetically, for search.TieLexicographic
+func (g wordGraph) Less(a, b search.GraphNode) bool {
+	return a.(*grapher.WordNode).Word < b.(*grapher.WordNode).Word
+}
+
+func (g wordGraph) Frequency(node search.GraphNode) float64 {
+	return g.frequencies[node.(*grapher.WordNode).Word]
+}
+
+// supports search.CostWeighted, whichever graph is wrapped
+func (g wordGraph) WeightedCost(from, to search.GraphNode) int {
+	return grapher.MoveGraph{}.WeightedCost(from, to)
+}
+
+// reads a whitespace-delimited word list (or a Hunspell dictionary)
+// and builds its graph
+func Load(r io.Reader, opts Options) (*Dictionary, error) {
+	return LoadContext(context.Background(), r, opts)
+}
+
+// as Load, but abandons loading and returns ctx.Err() if ctx is done first
+func LoadContext(ctx context.Context, r io.Reader, opts Options) (*Dictionary, error) {
+	var graph grapher.WordGraph
+	var count int
+	var err error
+	if opts.Hunspell {
+		graph, count, err = grapher.ScanHunspellLinkCompressContext(ctx, r, opts.Affix,
+			grapher.HunspellOptions{StemsOnly: opts.StemsOnly, Progress: opts.Progress, Moves: opts.Moves})
+	} else {
+		graph, count, err = grapher.ScanLinkCompressConcurrent(ctx, r,
+			grapher.ScanOptions{Progress: opts.Progress, Moves: opts.Moves})
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := &Dictionary{graph: graph, count: count, index: grapher.NewIndex(graph), moves: opts.Moves}
+	if opts.Frequencies != nil {
+		if d.frequencies, err = readFrequencies(opts.Frequencies); err != nil {
+			return nil, err
+		}
+	}
+	if opts.CacheSize > 0 {
+		d.cache = newCache(opts.CacheSize)
+	}
+	d.UseLandmarks(opts.Landmarks)
+	return d, nil
+}
+
+// records a change to the dictionary; d.mu must be held for writing
+func (d *Dictionary) changed() {
+	d.version++
+	if d.cache != nil {
+		d.cache.purge()
+	}
+}
+
+// selects perComponent landmarks in every component, to speed up searches
+// over large components; zero reverts to the Hamming distance alone
+func (d *Dictionary) UseLandmarks(perComponent int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.landmarks = nil
+	if perComponent > 0 {
+		d.landmarks = grapher.NewLandmarks(d.graph, perComponent)
+		d.landmarks.Moves = d.moves
+	}
+	d.changed()
+}
+
+// adds words to the dictionary, linking them into the graph,
+// and returns how many were new; no words are added
+// if any contains anything other than the letters a to z;
+// landmarks are discarded, since their distances may no longer hold
+func (d *Dictionary) Add(words ...string) (int, error) {
+	for _, word := range words {
+		if !grapher.IsLadderWord(word) {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidWord, word)
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	added := 0
+	for _, word := range words {
+		if node := grapher.InsertMoves(d.graph, word, d.moves); node != nil {
+			d.index.Assign(node)
+			added++
+		}
+	}
+	if added > 0 {
+		d.count += added
+		d.landmarks = nil
+		d.changed()
+	}
+	return added, nil
+}
+
+// removes words from the dictionary and returns how many were present;
+// landmarks are discarded, since their distances may no longer hold
+func (d *Dictionary) Remove(words ...string) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	removed := 0
+	for _, word := range words {
+		if grapher.Delete(d.graph, word) {
+			removed++
+		}
+	}
+	if removed > 0 {
+		d.count -= removed
+		d.landmarks = nil
+		d.changed()
+	}
+	return removed
+}
+
+// the number of distinct words
+func (d *Dictionary) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.count
+}
+
+// the underlying graph, for callers needing node-level access;
+// it must not be modified, nor used while words are added or removed
+func (d *Dictionary) Graph() grapher.WordGraph {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.graph
+}
+
+// the kinds of step a ladder may take, as given by Options.Moves;
+// substitutions alone if none were given
+func (d *Dictionary) Moves() Move {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.moves == 0 {
+		return MoveSubstitution
+	}
+	return d.moves
+}
+
+// nil if the word is not in the dictionary
+func (d *Dictionary) node(word string) *grapher.WordNode {
+	return d.graph[len(word)][word]
+}
+
+// reports whether word is in the dictionary
+func (d *Dictionary) Contains(word string) bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.node(word) != nil
+}
+
+// the words one transformation away from word
+func (d *Dictionary) Neighbours(word string) ([]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	node := d.node(word)
+	if node == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWord, word)
+	}
+	neighbours := make([]string, len(node.Neighbours))
+	for i, v := range node.Neighbours {
+		neighbours[i] = v.(*grapher.WordNode).Word
+	}
+	return neighbours, nil
+}
+
+// a ladder found by Search
+type Ladder struct {
+	Words []string
+	Moves []Move // Moves[i] is the kind of step from Words[i] to Words[i+1]
+	Cached bool // true if the ladder was remembered from an earlier query
+}
+
+// the shortest ladder from src to dst, including both words
+func (d *Dictionary) ShortestPath(ctx context.Context, src, dst string) ([]string, error) {
+	ladder, err := d.Search(ctx, src, dst, search.Options{})
+	return ladder.Words, err
+}
+
+// like ShortestPath, but within the limits of opts;
+// if the search is aborted, the error wraps ctx.Err(),
+// search.ErrMaxExpanded or search.ErrMaxDepth,
+// and any opts.Stats holds the partial statistics
+func (d *Dictionary) Search(ctx context.Context, src, dst string, opts search.Options) (Ladder, error) {
+	return d.SearchMulti(ctx, []string{src}, []string{dst}, opts)
+}
+
+// the shortest ladder from any of srcs to any of dsts;
+// since ladders never change a word's length, each length is searched
+// separately and words with no counterpart of the same length are ignored;
+// otherwise as Search, with statistics summed over the lengths searched;
+// ladders served from the cache have no statistics, and Cached set
+func (d *Dictionary) SearchMulti(ctx context.Context, srcs, dsts []string, opts search.Options) (Ladder, error) {
+	if len(srcs) == 0 || len(dsts) == 0 {
+		return Ladder{}, fmt.Errorf("%w: no words given", ErrUnknownWord)
+	}
+	stats := opts.Stats
+	if stats != nil {
+		*stats = search.Stats{}
+	}
+	opts.Stats = nil // so that the cache key is the same whoever asks
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	from, to := strings.Join(srcs, ","), strings.Join(dsts, ",")
+	key := cacheKey{d.version, from, to, opts}
+	if d.cache != nil {
+		if cached, ok := d.cache.get(key); ok {
+			if cached == nil {
+				return Ladder{Cached: true}, fmt.Errorf("%w: %s to %s", ErrNoPath, from, to)
+			}
+			return Ladder{Words: append([]string{}, cached...), Moves: d.steps(cached), Cached: true}, nil
+		}
+	}
+	srcNodes, err := d.nodes(srcs)
+	if err != nil {
+		return Ladder{}, err
+	}
+	dstNodes, err := d.nodes(dsts)
+	if err != nil {
+		return Ladder{}, err
+	}
+	var g search.Graph[search.GraphNode, int] = grapher.MoveGraph{Moves: d.moves}
+	if d.landmarks != nil {
+		g = d.landmarks
+	}
+	g = wordGraph{g, d.index, d.frequencies}
+	ws, _ := d.workspaces.Get().(*search.Workspace[search.GraphNode, int])
+	if ws == nil {
+		ws = new(search.Workspace[search.GraphNode, int])
+	}
+	defer d.workspaces.Put(ws)
+	var ladder Ladder
+	if stats != nil {
+		opts.Stats = new(search.Stats) // for the search of each length
+	}
+	searched := false
+	for _, letterCount := range sharedLengths(srcs, dsts) {
+		result := ws.AStarMulti(ctx, g, byLength(srcNodes, letterCount), byLength(dstNodes, letterCount), opts)
+		searched = true
+		if stats != nil {
+			*stats = addStats(*stats, *opts.Stats)
+		}
+		switch result.Outcome {
+		case search.Aborted:
+			return ladder, fmt.Errorf("search from %s to %s aborted: %w", from, to, result.Err)
+		case search.Found:
+			if ladder.Words == nil || len(result.Path) < len(ladder.Words) {
+				ladder.Words = words(result.Path)
+				ladder.Moves = d.steps(ladder.Words)
+			}
+		}
+	}
+	if !searched {
+		return Ladder{}, fmt.Errorf("%w: %s, %s", ErrLengthMismatch, from, to)
+	}
+	if d.cache != nil {
+		d.cache.put(key, append([]string(nil), ladder.Words...))
+	}
+	if ladder.Words == nil {
+		return ladder, fmt.Errorf("%w: %s to %s", ErrNoPath, from, to)
+	}
+	return ladder, nil
+}
+
+// the word lengths present in both a and b, in increasing order
+func sharedLengths(a, b []string) (lengths []int) {
+	inA := make(map[int]bool)
+	for _, word := range a {
+		inA[len(word)] = true
+	}
+	for _, word := range b {
+		if inA[len(word)] {
+			lengths = append(lengths, len(word))
+			inA[len(word)] = false
+		}
+	}
+	sort.Ints(lengths)
+	return
+}
+
+// the nodes whose words are letterCount letters long
+func byLength(nodes []search.GraphNode, letterCount int) (matching []search.GraphNode) {
+	for _, node := range nodes {
+		if len(node.(*grapher.WordNode).Word) == letterCount {
+			matching = append(matching, node)
+		}
+	}
+	return
+}
+
+// combines the statistics of consecutive searches
+func addStats(a, b search.Stats) search.Stats {
+	a.Expanded += b.Expanded
+	a.Generated += b.Generated
+	a.Reopened += b.Reopened
+	if b.PeakQueue > a.PeakQueue {
+		a.PeakQueue = b.PeakQueue
+	}
+	if b.Depth > a.Depth {
+		a.Depth = b.Depth
+	}
+	a.Elapsed += b.Elapsed
+	return a
+}
+
+// the nodes of words, or an error naming the first unknown word
+func (d *Dictionary) nodes(words []string) ([]search.GraphNode, error) {
+	nodes := make([]search.GraphNode, len(words))
+	for i, word := range words {
+		node := d.node(word)
+		if node == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownWord, word)
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+// the kind of each step of a ladder; the words must be in the dictionary,
+// and d.mu held
+func (d *Dictionary) steps(words []string) []Move {
+	moves := make([]Move, len(words)-1)
+	for i := range moves {
+		moves[i] = d.node(words[i]).NeighbourMove(d.node(words[i+1]))
+	}
+	return moves
+}
+
+// panics if path contains non-*grapher.WordNode values
+func words(path []search.GraphNode) []string {
+	out := make([]string, len(path))
+	for i, v := range path {
+		out[i] = v.(*grapher.WordNode).Word
+	}
+	return out
+}
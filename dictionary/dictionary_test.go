@@ -0,0 +1,280 @@
+package dictionary
+
+import (
+		"testing"
+		"strings"
+		"reflect"
+		"errors"
+		"context"
+		"github.com/nerophon/dictdash/search"
+)
+
+func loadTestDictionary(t *testing.T) *Dictionary {
+	d, err := Load(strings.NewReader("cold cord card ward warm word wold zzzz cat"), Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	return d
+}
+
+func TestLoad(t *testing.T) {
+	d := loadTestDictionary(t)
+	if d.Len() != 9 {
+		t.Errorf("Len(), want=9, got=%d", d.Len())
+	}
+	hunspell, err := Load(strings.NewReader("1\ncold/S\n"),
+		Options{Hunspell: true, Affix: strings.NewReader("SFX S Y 1\nSFX S 0 s .\n")})
+	if err != nil || !hunspell.Contains("colds") {
+		t.Errorf("Load(hunspell), want colds, err=%v", err)
+	}
+}
+
+func TestContains(t *testing.T) {
+	d := loadTestDictionary(t)
+	cases := []struct {
+		in string
+		want bool
+	}{
+		{"cold", true},
+		{"warm", true},
+		{"hot", false},
+		{"", false},
+	}
+	for _, c := range cases {
+		if got := d.Contains(c.in); got != c.want {
+			t.Errorf("Contains(%s), want=%t, got=%t", c.in, c.want, got)
+		}
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	d := loadTestDictionary(t)
+	got, err := d.Neighbours("cord")
+	want := []string{"word", "card", "cold"}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbours(cord), want=%v, got=%v, err=%v", want, got, err)
+	}
+	if _, err = d.Neighbours("hot"); !errors.Is(err, ErrUnknownWord) {
+		t.Errorf("Neighbours(hot), want ErrUnknownWord, got=%v", err)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	d := loadTestDictionary(t)
+	cases := []struct {
+		src, dst string
+		want []string
+		wantErr error
+	}{
+		{"cold", "warm", []string{"cold", "cord", "card", "ward", "warm"}, nil},
+		{"cold", "cold", []string{"cold"}, nil},
+		{"cold", "cat", nil, ErrLengthMismatch},
+		{"cold", "hold", nil, ErrUnknownWord},
+		{"hold", "cold", nil, ErrUnknownWord},
+		{"cold", "zzzz", nil, ErrNoPath},
+	}
+	for _, c := range cases {
+		got, err := d.ShortestPath(context.Background(), c.src, c.dst)
+		if !errors.Is(err, c.wantErr) || (c.wantErr == nil && err != nil) {
+			t.Errorf("ShortestPath(%s, %s), wantErr=%v, gotErr=%v", c.src, c.dst, c.wantErr, err)
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("ShortestPath(%s, %s), want=%v, got=%v", c.src, c.dst, c.want, got)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := d.ShortestPath(ctx, "cold", "warm"); !errors.Is(err, context.Canceled) {
+		t.Errorf("ShortestPath(cancelled), want context.Canceled, got=%v", err)
+	}
+}
+
+func TestSearchLimits(t *testing.T) {
+	d := loadTestDictionary(t)
+	cases := []struct {
+		opts search.Options
+		wantErr error
+	}{
+		{search.Options{}, nil},
+		{search.Options{MaxExpanded: 2}, search.ErrMaxExpanded},
+		{search.Options{MaxDepth: 3}, search.ErrMaxDepth},
+	}
+	for _, c := range cases {
+		var stats search.Stats
+		c.opts.Stats = &stats
+		_, err := d.Search(context.Background(), "cold", "warm", c.opts)
+		if !errors.Is(err, c.wantErr) || (c.wantErr == nil && err != nil) {
+			t.Errorf("Search(%+v), wantErr=%v, gotErr=%v", c.opts, c.wantErr, err)
+		}
+		if stats.Expanded == 0 {
+			t.Errorf("Search(%+v), want partial statistics, got=%+v", c.opts, stats)
+		}
+	}
+}
+
+func TestUseLandmarks(t *testing.T) {
+	d := loadTestDictionary(t)
+	d.UseLandmarks(2)
+	got, err := d.ShortestPath(context.Background(), "cold", "warm")
+	if err != nil || len(got) != 5 {
+		t.Errorf("ShortestPath(landmarks), want 5 words, got=%v, err=%v", got, err)
+	}
+	d.UseLandmarks(0)
+	if d.landmarks != nil {
+		t.Errorf("UseLandmarks(0), want landmarks removed")
+	}
+}
+
+func TestSearchMulti(t *testing.T) {
+	d := loadTestDictionary(t)
+	cases := []struct {
+		srcs, dsts []string
+		want []string
+		wantErr error
+	}{
+		{[]string{"cold"}, []string{"warm", "word"}, []string{"cold", "wold", "word"}, nil},
+		{[]string{"card", "cold"}, []string{"warm"}, []string{"card", "ward", "warm"}, nil},
+		{[]string{"cold", "cat"}, []string{"warm"}, []string{"cold", "wold", "word", "ward", "warm"}, nil},
+		{[]string{"cold"}, []string{"cat"}, nil, ErrLengthMismatch},
+		{[]string{"cold"}, []string{"warm", "hold"}, nil, ErrUnknownWord},
+		{nil, []string{"warm"}, nil, ErrUnknownWord},
+		{[]string{"cold", "warm"}, []string{"zzzz"}, nil, ErrNoPath},
+	}
+	for _, c := range cases {
+		got, err := d.SearchMulti(context.Background(), c.srcs, c.dsts, search.Options{})
+		if !errors.Is(err, c.wantErr) || (c.wantErr == nil && err != nil) {
+			t.Errorf("SearchMulti(%v, %v), wantErr=%v, gotErr=%v", c.srcs, c.dsts, c.wantErr, err)
+		}
+		if !reflect.DeepEqual(got.Words, c.want) {
+			t.Errorf("SearchMulti(%v, %v), want=%v, got=%v", c.srcs, c.dsts, c.want, got.Words)
+		}
+	}
+}
+
+func TestTieBreak(t *testing.T) {
+	d := loadTestDictionary(t)
+	// cold to warm has three ladders of four transformations
+	cases := []struct {
+		frequencies string
+		tieBreak search.TieBreak
+		want []string
+	}{
+		{"", search.TieLexicographic, []string{"cold", "cord", "card", "ward", "warm"}},
+		{"", search.TieFrequency, []string{"cold", "cord", "card", "ward", "warm"}},
+		{"wold 10\ncard 5\nword 2", search.TieFrequency, []string{"cold", "wold", "word", "ward", "warm"}},
+		{"# counts\ncard 5\nword 2\n", search.TieFrequency, []string{"cold", "cord", "card", "ward", "warm"}},
+	}
+	for _, c := range cases {
+		if err := d.LoadFrequencies(strings.NewReader(c.frequencies)); err != nil {
+			t.Fatalf("LoadFrequencies(%q), unexpected error=%v", c.frequencies, err)
+		}
+		ladder, err := d.Search(context.Background(), "cold", "warm", search.Options{TieBreak: c.tieBreak})
+		if err != nil || !reflect.DeepEqual(ladder.Words, c.want) {
+			t.Errorf("Search(%q, %d), want=%v, got=%v, err=%v", c.frequencies, c.tieBreak, c.want, ladder.Words, err)
+		}
+	}
+	if d.Frequency("card") != 5 || d.Frequency("warm") != 0 {
+		t.Errorf("Frequency(), want card=5 and warm=0, got card=%v and warm=%v", d.Frequency("card"), d.Frequency("warm"))
+	}
+	if err := d.LoadFrequencies(strings.NewReader("cold many")); err == nil {
+		t.Errorf("LoadFrequencies(cold many), want error")
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	var last Progress
+	input := "cold cord card ward warm word wold zzzz cat"
+	d, err := LoadContext(context.Background(), strings.NewReader(input),
+		Options{Progress: func(p Progress) { last = p }})
+	if err != nil || d.Len() != 9 {
+		t.Fatalf("LoadContext(), want 9 words, err=%v", err)
+	}
+	want := Progress{BytesRead: int64(len(input)), WordsScanned: 9, Buckets: 2, BucketsLinked: 2, BucketsCompressed: 2}
+	if last != want {
+		t.Errorf("LoadContext(), want final progress=%+v, got=%+v", want, last)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = LoadContext(ctx, strings.NewReader(input), Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("LoadContext(cancelled), want context.Canceled, got=%v", err)
+	}
+}
+
+func TestMoves(t *testing.T) {
+	input := "cold clod clad glad goad"
+	d, err := Load(strings.NewReader(input), Options{Moves: MoveSubstitution | MoveTransposition, CacheSize: 4})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	wantWords := []string{"cold", "clod", "clad", "glad", "goad"}
+	wantMoves := []Move{MoveTransposition, MoveSubstitution, MoveSubstitution, MoveSubstitution}
+	for _, cached := range []bool{false, true} {
+		ladder, err := d.Search(context.Background(), "cold", "goad", search.Options{})
+		if err != nil || ladder.Cached != cached || !reflect.DeepEqual(ladder.Words, wantWords) || !reflect.DeepEqual(ladder.Moves, wantMoves) {
+			t.Errorf("Search(cold, goad), cached=%t, want=%v %v, got=%+v, err=%v", cached, wantWords, wantMoves, ladder, err)
+		}
+	}
+	if _, err := d.Add("dloc"); err != nil {
+		t.Fatalf("Add(dloc), unexpected error=%v", err)
+	}
+	if got, _ := d.Neighbours("dloc"); len(got) != 0 {
+		t.Errorf("Add(dloc), want no anagram neighbours without MoveAnagram, got=%v", got)
+	}
+}
+
+func TestEnginesAndCosts(t *testing.T) {
+	// abcd to cbad is an anagram, or two substitutions by way of cbcd
+	d, err := Load(strings.NewReader("abcd cbad cbcd"), Options{Moves: MoveSubstitution | MoveAnagram, CacheSize: 4})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	cases := []struct {
+		opts search.Options
+		want string
+	}{
+		{search.Options{}, "abcd cbad"},
+		{search.Options{Engine: search.EngineDijkstra}, "abcd cbad"},
+		{search.Options{Engine: search.EngineBFS}, "abcd cbad"},
+		{search.Options{Cost: search.CostWeighted}, "abcd cbcd cbad"},
+		{search.Options{Cost: search.CostWeighted, Engine: search.EngineDijkstra}, "abcd cbcd cbad"},
+		{search.Options{Cost: search.CostWeighted, Engine: search.EngineBFS}, "abcd cbad"}, // hops alone
+	}
+	for _, c := range cases {
+		ladder, err := d.Search(context.Background(), "abcd", "cbad", c.opts)
+		if got := strings.Join(ladder.Words, " "); err != nil || got != c.want {
+			t.Errorf("Search(%+v), want=%q, got=%q, err=%v", c.opts, c.want, got, err)
+		}
+		// and the best command ranks the same ladders
+		ladders, err := d.ShortestLadders(context.Background(), "abcd", "cbad", MaxRanked, c.opts)
+		if got := joinLadders(ladders); err != nil || len(got) != 1 || got[0] != c.want {
+			t.Errorf("ShortestLadders(%+v), want=%q, got=%q, err=%v", c.opts, c.want, got, err)
+		}
+	}
+}
+
+// tie-breaks that compare paths retrace them with the cost the search used
+func TestWeightedTieBreaks(t *testing.T) {
+	cases := []struct {
+		words string
+		want map[search.TieBreak]string
+	}{
+		// the swap costs two, and is the only way
+		{"ab ba", map[search.TieBreak]string{search.TieLexicographic: "ab ba", search.TieFrequency: "ab ba"}},
+		// as do the two substitutions by way of aa, which sorts first
+		{"ab ba aa", map[search.TieBreak]string{search.TieLexicographic: "ab aa ba"}},
+	}
+	for _, c := range cases {
+		d, err := Load(strings.NewReader(c.words), Options{Moves: MoveSubstitution | MoveTransposition})
+		if err != nil {
+			t.Fatalf("Load(), unexpected error=%v", err)
+		}
+		for _, tieBreak := range []search.TieBreak{search.TieLexicographic, search.TieFrequency, search.TieHigherEstimate, search.TieRandom} {
+			opts := search.Options{Cost: search.CostWeighted, TieBreak: tieBreak}
+			ladder, err := d.Search(context.Background(), "ab", "ba", opts)
+			got := strings.Join(ladder.Words, " ")
+			if want, ok := c.want[tieBreak]; err != nil || (ok && got != want) || (got != "ab ba" && got != "ab aa ba") {
+				t.Errorf("Search(%s, %+v), want a ladder costing two, got=%q, err=%v", c.words, opts, got, err)
+			}
+		}
+	}
+}
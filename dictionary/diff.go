@@ -0,0 +1,164 @@
+package dictionary
+
+import (
+		"sort"
+		"errors"
+		"context"
+		"github.com/nerophon/dictdash/grapher"
+)
+
+// a set of words connected by ladders, named by its alphabetically first word
+type Component struct {
+	Word string
+	Size int
+}
+
+// a component of one dictionary made up of words from several components
+// of another; Parts are ordered largest first, and only count the words
+// the two dictionaries share
+type ComponentChange struct {
+	Component Component
+	Parts []Component
+}
+
+// the differences between two versions of a dictionary
+type Changes struct {
+	Added, Removed []string // in alphabetical order
+	// components of after joining words that were in separate components
+	// of before, and components of before whose words are separated in after;
+	// largest first
+	Merged, Split []ComponentChange
+}
+
+// the words added and removed from before to after, and how the
+// components of the words present in both have merged or split;
+// a component of either holding no shared words is not reported;
+// each dictionary is read under its own lock in turn, never both at once,
+// so that Diff(a, b) and Diff(b, a) cannot deadlock with a writer
+func Diff(before, after *Dictionary) Changes {
+	var c Changes
+	beforeOf, beforeComponents := before.components()
+	afterOf, afterComponents := after.components()
+	for word := range beforeOf {
+		if _, ok := afterOf[word]; !ok {
+			c.Removed = append(c.Removed, word)
+		}
+	}
+	for word := range afterOf {
+		if _, ok := beforeOf[word]; !ok {
+			c.Added = append(c.Added, word)
+		}
+	}
+	sort.Strings(c.Added)
+	sort.Strings(c.Removed)
+
+	// for each component, the components of the other dictionary
+	// holding its shared words, and how many of them each holds
+	merges := make(map[int]map[int]int)
+	splits := make(map[int]map[int]int)
+	for word, b := range beforeOf {
+		a, ok := afterOf[word]
+		if !ok {
+			continue
+		}
+		if merges[a] == nil {
+			merges[a] = make(map[int]int)
+		}
+		merges[a][b]++
+		if splits[b] == nil {
+			splits[b] = make(map[int]int)
+		}
+		splits[b][a]++
+	}
+	c.Merged = componentChanges(merges, afterComponents, beforeComponents)
+	c.Split = componentChanges(splits, beforeComponents, afterComponents)
+	return c
+}
+
+// as componentsOf, for the dictionary's graph
+func (d *Dictionary) components() (map[string]int, []Component) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return componentsOf(d.graph)
+}
+
+// each word's component, as an index into the returned components
+func componentsOf(graph grapher.WordGraph) (map[string]int, []Component) {
+	of := make(map[string]int)
+	var components []Component
+	for i, nodes := range graph.Components() {
+		components = append(components, Component{nodes[0].Word, len(nodes)})
+		for _, node := range nodes {
+			of[node.Word] = i
+		}
+	}
+	return of, components
+}
+
+// the changes for every component whose shared words came from more than
+// one component of the other dictionary
+func componentChanges(sources map[int]map[int]int, components, others []Component) (changes []ComponentChange) {
+	for i, parts := range sources {
+		if len(parts) < 2 {
+			continue
+		}
+		change := ComponentChange{Component: components[i]}
+		for j, shared := range parts {
+			change.Parts = append(change.Parts, Component{others[j].Word, shared})
+		}
+		sortComponents(change.Parts)
+		changes = append(changes, change)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return bigger(changes[i].Component, changes[j].Component)
+	})
+	return
+}
+
+func sortComponents(components []Component) {
+	sort.Slice(components, func(i, j int) bool { return bigger(components[i], components[j]) })
+}
+
+// orders components largest first, then alphabetically
+func bigger(a, b Component) bool {
+	if a.Size != b.Size {
+		return a.Size > b.Size
+	}
+	return a.Word < b.Word
+}
+
+// the lengths, in steps, of the shortest ladders for a pair in two
+// versions of a dictionary; -1 where there is none, or a word is missing
+type LadderChange struct {
+	Pair
+	Before, After int
+}
+
+// the ladder lengths of each pair in before and after, in the order given
+func CompareLadders(ctx context.Context, before, after *Dictionary, pairs []Pair) ([]LadderChange, error) {
+	changes := make([]LadderChange, len(pairs))
+	for i, pair := range pairs {
+		changes[i].Pair = pair
+		var err error
+		if changes[i].Before, err = before.ladderLength(ctx, pair); err != nil {
+			return nil, err
+		}
+		if changes[i].After, err = after.ladderLength(ctx, pair); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+// the length of the shortest ladder for pair, or -1 if there is none;
+// other errors, such as ctx being done, are returned
+func (d *Dictionary) ladderLength(ctx context.Context, pair Pair) (int, error) {
+	ladder, err := d.ShortestPath(ctx, pair.Src, pair.Dst)
+	switch {
+	case errors.Is(err, ErrNoPath), errors.Is(err, ErrUnknownWord):
+		return -1, nil
+	case err != nil:
+		return 0, err
+	}
+	return len(ladder) - 1, nil
+}
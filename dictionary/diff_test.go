@@ -0,0 +1,99 @@
+package dictionary
+
+import (
+		"testing"
+		"strings"
+		"reflect"
+		"errors"
+		"context"
+		"sync"
+)
+
+func TestDiff(t *testing.T) {
+	before, err := Load(strings.NewReader("cold cord card ward warm bat cat hat zzz zzy"), Options{})
+	if err != nil {
+		t.Fatalf("Load(before), unexpected error=%v", err)
+	}
+	// removing card splits cold from warm; zat and zzt join hat to zzz
+	after, err := Load(strings.NewReader("cold cord ward warm bat cat hat zzz zzy zat zzt"), Options{})
+	if err != nil {
+		t.Fatalf("Load(after), unexpected error=%v", err)
+	}
+	want := Changes{
+		Added: []string{"zat", "zzt"},
+		Removed: []string{"card"},
+		Merged: []ComponentChange{{Component{"bat", 7}, []Component{{"bat", 3}, {"zzy", 2}}}},
+		Split: []ComponentChange{{Component{"card", 5}, []Component{{"cold", 2}, {"ward", 2}}}},
+	}
+	if got := Diff(before, after); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff(), want=%+v, got=%+v", want, got)
+	}
+	if got := Diff(before, before); !reflect.DeepEqual(got, Changes{}) {
+		t.Errorf("Diff(before, before), want no changes, got=%+v", got)
+	}
+
+	pairs := []Pair{{"cold", "warm"}, {"cat", "hat"}, {"bat", "zzz"}, {"cord", "card"}}
+	ladders, err := CompareLadders(context.Background(), before, after, pairs)
+	wantLadders := []LadderChange{
+		{Pair{"cold", "warm"}, 4, -1},
+		{Pair{"cat", "hat"}, 1, 1},
+		{Pair{"bat", "zzz"}, -1, 3},
+		{Pair{"cord", "card"}, 1, -1},
+	}
+	if err != nil || !reflect.DeepEqual(ladders, wantLadders) {
+		t.Errorf("CompareLadders(), want=%v, got=%v, err=%v", wantLadders, ladders, err)
+	}
+	if _, err = CompareLadders(context.Background(), before, after, []Pair{{"cold", "cat"}}); !errors.Is(err, ErrLengthMismatch) {
+		t.Errorf("CompareLadders(cold, cat), want ErrLengthMismatch, got=%v", err)
+	}
+}
+
+// a writer waiting on either dictionary must not leave Diff holding
+// one read lock while blocked on another; the window for that is narrow,
+// so this only catches a regression now and then
+func TestConcurrentDiff(t *testing.T) {
+	a, _ := Load(strings.NewReader("cold cord card"), Options{})
+	b, _ := Load(strings.NewReader("cold cord ward"), Options{})
+	var wg sync.WaitGroup
+	for _, d := range []*Dictionary{a, b} {
+		wg.Add(1)
+		go func(d *Dictionary) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				d.Add("wold")
+				d.Remove("wold")
+			}
+		}(d)
+	}
+	for _, pair := range [][2]*Dictionary{{a, b}, {b, a}, {a, a}} {
+		wg.Add(1)
+		go func(before, after *Dictionary) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				Diff(before, after)
+			}
+		}(pair[0], pair[1])
+	}
+	wg.Wait()
+}
+
+func TestReadPairs(t *testing.T) {
+	pairs, err := ReadPairs(strings.NewReader("# puzzles\ncold warm\n\n  head tail  \n"))
+	want := []Pair{{"cold", "warm"}, {"head", "tail"}}
+	if err != nil || !reflect.DeepEqual(pairs, want) {
+		t.Errorf("ReadPairs(), want=%v, got=%v, err=%v", want, pairs, err)
+	}
+	cases := []struct {
+		in string
+		want string
+	}{
+		{"cold warm\ncold\n", "pair line 2: want two words"},
+		{"cold warm hot\n", "pair line 1: want two words"},
+		{"\nhead tails\n", "pair line 2: words are of different lengths: head tails"},
+	}
+	for _, c := range cases {
+		if _, err := ReadPairs(strings.NewReader(c.in)); err == nil || err.Error() != c.want {
+			t.Errorf("ReadPairs(%q), want error %q, got=%v", c.in, c.want, err)
+		}
+	}
+}
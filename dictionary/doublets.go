@@ -0,0 +1,41 @@
+package dictionary
+
+import (
+		"errors"
+		"context"
+		"github.com/nerophon/dictdash/search"
+)
+
+// Lewis Carroll's doublets are puzzles of pairs of related words, such as
+// head and tail, to be joined by a ladder
+
+// a puzzle and its answer
+type Doublet struct {
+	Pair
+	Ladder Ladder // with no words if there is no answer
+	// why there is no answer, wrapping ErrUnknownWord or ErrNoPath
+	Err error
+}
+
+// the number of steps of the answer, or -1 if there is none
+func (d Doublet) Steps() int {
+	return len(d.Ladder.Words) - 1
+}
+
+// the shortest ladder for each of pairs, searching as Search does with opts;
+// a pair without one is given the reason in its Err, and an error is
+// returned only if a search is abandoned
+func (d *Dictionary) SolveDoublets(ctx context.Context, pairs []Pair, opts search.Options) ([]Doublet, error) {
+	doublets := make([]Doublet, len(pairs))
+	for i, pair := range pairs {
+		ladder, err := d.Search(ctx, pair.Src, pair.Dst, opts)
+		if err != nil && !errors.Is(err, ErrNoPath) && !errors.Is(err, ErrUnknownWord) {
+			return nil, err
+		}
+		doublets[i] = Doublet{Pair: pair, Err: err}
+		if err == nil {
+			doublets[i].Ladder = ladder
+		}
+	}
+	return doublets, nil
+}
@@ -0,0 +1,43 @@
+package dictionary
+
+import (
+		"testing"
+		"strings"
+		"errors"
+		"context"
+		"github.com/nerophon/dictdash/search"
+)
+
+func TestSolveDoublets(t *testing.T) {
+	d, err := Load(strings.NewReader("cold cord card ward warm cat hat zzz"), Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	pairs := []Pair{{"cold", "warm"}, {"cat", "zzz"}, {"cat", "dog"}, {"hat", "hat"}}
+	doublets, err := d.SolveDoublets(context.Background(), pairs, search.Options{})
+	if err != nil {
+		t.Fatalf("SolveDoublets(), unexpected error=%v", err)
+	}
+	cases := []struct {
+		steps int
+		words string
+		err error
+	}{
+		{4, "cold cord card ward warm", nil},
+		{-1, "", ErrNoPath},
+		{-1, "", ErrUnknownWord},
+		{0, "hat", nil},
+	}
+	for i, c := range cases {
+		got := doublets[i]
+		if got.Pair != pairs[i] || got.Steps() != c.steps || strings.Join(got.Ladder.Words, " ") != c.words || !errors.Is(got.Err, c.err) || (c.err == nil) != (got.Err == nil) {
+			t.Errorf("SolveDoublets(%v), want=%d %q %v, got=%d %q %v", pairs[i], c.steps, c.words, c.err, got.Steps(), got.Ladder.Words, got.Err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = d.SolveDoublets(ctx, []Pair{{"cord", "ward"}}, search.Options{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("SolveDoublets(cancelled), want context.Canceled, got %v", err)
+	}
+}
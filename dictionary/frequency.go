@@ -0,0 +1,59 @@
+package dictionary
+
+import (
+		"io"
+		"fmt"
+		"bufio"
+		"strings"
+		"strconv"
+)
+
+// reads a word frequency list: one word per line followed by its count,
+// separated by whitespace; blank lines and lines starting with '#' are ignored,
+// and a word listed twice keeps its later count
+func readFrequencies(r io.Reader) (map[string]float64, error) {
+	frequencies := make(map[string]float64)
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("frequency line %d: want a word and a count", lineNum)
+		}
+		count, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("frequency line %d: %q is not a count", lineNum, fields[1])
+		}
+		frequencies[fields[0]] = count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return frequencies, nil
+}
+
+// replaces the dictionary's word frequencies, used by search.TieFrequency,
+// with those read from r in the format described for Options.Frequencies
+func (d *Dictionary) LoadFrequencies(r io.Reader) error {
+	frequencies, err := readFrequencies(r)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.frequencies = frequencies
+	d.changed()
+	return nil
+}
+
+// how often word occurs, according to the loaded frequencies;
+// zero if unknown
+func (d *Dictionary) Frequency(word string) float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.frequencies[word]
+}
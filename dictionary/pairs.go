@@ -0,0 +1,40 @@
+package dictionary
+
+import (
+		"io"
+		"fmt"
+		"bufio"
+		"strings"
+)
+
+// a puzzle: a ladder to be found from Src to Dst
+type Pair struct {
+	Src, Dst string
+}
+
+// reads a list of puzzles: one pair of words per line, separated by
+// whitespace; blank lines and lines starting with '#' are ignored,
+// and the words of a pair must be of the same length
+func ReadPairs(r io.Reader) ([]Pair, error) {
+	var pairs []Pair
+	scanner := bufio.NewScanner(r)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("pair line %d: want two words", lineNum)
+		}
+		if len(fields[0]) != len(fields[1]) {
+			return nil, fmt.Errorf("pair line %d: %w: %s %s", lineNum, ErrLengthMismatch, fields[0], fields[1])
+		}
+		pairs = append(pairs, Pair{fields[0], fields[1]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
@@ -0,0 +1,254 @@
+package dictionary
+
+import (
+		"fmt"
+		"math"
+		"sort"
+		"context"
+		"strings"
+		"github.com/nerophon/dictdash/grapher"
+		"github.com/nerophon/dictdash/search"
+)
+
+// of the shortest ladders between two words, some are more pleasant than
+// others: they use common words, change different letters from step to step,
+// and avoid steps between spellings of the same word; a Scorer rates a
+// ladder by one such criterion, and BestLadders ranks the shortest ladders
+// by a Scorer
+
+// rates a ladder, given its words including both ends; higher is better
+type Scorer interface {
+	Score(words []string) float64
+}
+
+// lets an ordinary function be used as a Scorer
+type ScorerFunc func(words []string) float64
+
+func (f ScorerFunc) Score(words []string) float64 {
+	return f(words)
+}
+
+// one Scorer of several, with its weight
+type Criterion struct {
+	Name string // for display
+	Scorer
+	Weight float64
+}
+
+// Scorers combined by weight, as the sum of their weighted scores
+type Criteria []Criterion
+
+func (c Criteria) Score(words []string) (score float64) {
+	for _, criterion := range c {
+		score += criterion.Weight * criterion.Score(words)
+	}
+	return
+}
+
+// the most shortest ladders that BestLadders considers, taken alphabetically;
+// pairs of long words can be joined by millions
+const MaxRanked = 10000
+
+// scores from zero to one: the fraction of steps that change letters in
+// other places than the step before; a ladder of one step scores one
+var VariedPositions = ScorerFunc(func(words []string) float64 {
+	if len(words) < 3 {
+		return 1
+	}
+	varied := 0
+	for i := 2; i < len(words); i++ {
+		if changed(words[i-2], words[i-1]) != changed(words[i-1], words[i]) {
+			varied++
+		}
+	}
+	return float64(varied) / float64(len(words) - 2)
+})
+
+// the positions at which a and b differ, as a bit set
+func changed(a, b string) (positions uint64) {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			positions |= 1 << i // beyond 64 letters, positions are not told apart
+		}
+	}
+	return
+}
+
+// scores minus the number of steps that are likely between two spellings of
+// one word, as jounce to jaunce: steps that swap one vowel for another
+// next to a vowel
+var NearDuplicates = ScorerFunc(func(words []string) float64 {
+	duplicates := 0
+	for i := 1; i < len(words); i++ {
+		if nearDuplicate(words[i-1], words[i]) {
+			duplicates++
+		}
+	}
+	return -float64(duplicates)
+})
+
+func nearDuplicate(a, b string) bool {
+	positions := changed(a, b)
+	if len(a) != len(b) || positions & (positions - 1) != 0 {
+		return false // not a single substitution
+	}
+	vowel := func(s string, i int) bool { return i >= 0 && i < len(s) && strings.IndexByte("aeiou", s[i]) >= 0 }
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			return vowel(a, i) && vowel(b, i) && (vowel(a, i-1) || vowel(a, i+1))
+		}
+	}
+	return false
+}
+
+// a Scorer of the mean, over the words between the ends of a ladder, of
+// the logarithm of one more than each word's frequency, so that a ladder
+// of common words scores highest; every ladder scores zero if no
+// frequencies are loaded
+func (d *Dictionary) CommonWords() Scorer {
+	return ScorerFunc(func(words []string) float64 {
+		if len(words) < 3 {
+			return 0
+		}
+		var total float64
+		for _, word := range words[1:len(words)-1] {
+			total += math.Log1p(d.Frequency(word))
+		}
+		return total / float64(len(words) - 2)
+	})
+}
+
+// common words, if frequencies are loaded, varied positions and
+// few near-duplicates, in that order
+func (d *Dictionary) DefaultCriteria() Criteria {
+	var criteria Criteria
+	d.mu.RLock()
+	frequent := len(d.frequencies) > 0
+	d.mu.RUnlock()
+	if frequent {
+		criteria = append(criteria, Criterion{"common", d.CommonWords(), 1})
+	}
+	return append(criteria, Criterion{"varied", VariedPositions, 2}, Criterion{"duplicates", NearDuplicates, 2})
+}
+
+// a ladder with its score
+type ScoredLadder struct {
+	Ladder
+	Score float64
+}
+
+// the n best of the shortest ladders from src to dst by scorer, best first,
+// and the number of shortest ladders considered, at most MaxRanked;
+// equal scores are ordered alphabetically by their words
+func (d *Dictionary) BestLadders(ctx context.Context, src, dst string, scorer Scorer, n int, opts search.Options) ([]ScoredLadder, int, error) {
+	// the kinds of step are read while the ladders still match the graph,
+	// but scored after unlocking, as scorers may lock the dictionary too
+	d.mu.RLock()
+	ladders, err := d.shortestLaddersLocked(ctx, src, dst, MaxRanked, opts)
+	moves := make([][]Move, len(ladders))
+	for i, words := range ladders {
+		moves[i] = d.steps(words)
+	}
+	d.mu.RUnlock()
+	if err != nil {
+		return nil, 0, err
+	}
+	scored := make([]ScoredLadder, len(ladders))
+	for i, words := range ladders {
+		scored[i] = ScoredLadder{Ladder{Words: words, Moves: moves[i]}, scorer.Score(words)}
+	}
+	sort.SliceStable(scored, func(i, j int) bool { return scored[i].Score > scored[j].Score })
+	return scored[:min(n, len(scored))], len(ladders), nil
+}
+
+// up to limit of the shortest ladders from src to dst, in alphabetical order
+// of their words; a ladder's length is its cost under opts.Cost, as Search
+// would find it, except that with search.EngineBFS every step costs one
+func (d *Dictionary) ShortestLadders(ctx context.Context, src, dst string, limit int, opts search.Options) ([][]string, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.shortestLaddersLocked(ctx, src, dst, limit, opts)
+}
+
+// as ShortestLadders, with d.mu held
+func (d *Dictionary) shortestLaddersLocked(ctx context.Context, src, dst string, limit int, opts search.Options) ([][]string, error) {
+	if len(src) != len(dst) {
+		return nil, fmt.Errorf("%w: %s, %s", ErrLengthMismatch, src, dst)
+	}
+	srcNode, dstNode := d.node(src), d.node(dst)
+	if srcNode == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWord, src)
+	}
+	if dstNode == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownWord, dst)
+	}
+	// the distance of every word from dst, so that a step costing c from
+	// a word at distance k to one at k-c is on a shortest ladder
+	weight := stepWeight(opts)
+	toDst := distancesTo(dstNode, weight)
+	if _, ok := toDst[srcNode]; !ok {
+		return nil, fmt.Errorf("%w: %s to %s", ErrNoPath, src, dst)
+	}
+	var ladders [][]string
+	path := []string{src}
+	var extend func(node *grapher.WordNode) error
+	extend = func(node *grapher.WordNode) error {
+		if len(ladders) >= limit {
+			return nil
+		}
+		if node == dstNode {
+			ladders = append(ladders, append([]string(nil), path...))
+			return ctx.Err()
+		}
+		for _, next := range node.SortedNeighbours() {
+			if distance, ok := toDst[next]; ok && distance == toDst[node] - weight(node, next) {
+				path = append(path, next.Word)
+				if err := extend(next); err != nil {
+					return err
+				}
+				path = path[:len(path)-1]
+			}
+		}
+		return nil
+	}
+	if err := extend(srcNode); err != nil {
+		return nil, fmt.Errorf("listing ladders from %s to %s aborted: %w", src, dst, err)
+	}
+	return ladders, nil
+}
+
+// the cost of a step under opts, as ShortestLadders counts it
+func stepWeight(opts search.Options) func(from, to *grapher.WordNode) int {
+	if opts.Cost == search.CostWeighted && opts.Engine != search.EngineBFS {
+		return func(from, to *grapher.WordNode) int { return from.NeighbourMove(to).Weight() }
+	}
+	return func(from, to *grapher.WordNode) int { return 1 }
+}
+
+// the distance of every word reachable from node, where steps cost at
+// least one; words are kept in a bucket per distance, which is cheaper
+// than a heap for the small weights of steps
+func distancesTo(node *grapher.WordNode, weight func(from, to *grapher.WordNode) int) map[*grapher.WordNode]int {
+	distances := map[*grapher.WordNode]int{node: 0}
+	buckets := [][]*grapher.WordNode{{node}}
+	for distance := 0; distance < len(buckets); distance++ {
+		for _, current := range buckets[distance] {
+			if distances[current] != distance {
+				continue // since reached more cheaply
+			}
+			for _, neighbour := range current.Neighbours {
+				next := neighbour.(*grapher.WordNode)
+				cost := distance + weight(current, next)
+				if known, ok := distances[next]; ok && known <= cost {
+					continue
+				}
+				distances[next] = cost
+				for len(buckets) <= cost {
+					buckets = append(buckets, nil)
+				}
+				buckets[cost] = append(buckets[cost], next)
+			}
+		}
+	}
+	return distances
+}
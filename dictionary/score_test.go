@@ -0,0 +1,125 @@
+package dictionary
+
+import (
+		"testing"
+		"strings"
+		"errors"
+		"context"
+		"slices"
+		"fmt"
+		"sync"
+		"github.com/nerophon/dictdash/search"
+)
+
+func TestScorers(t *testing.T) {
+	cases := []struct {
+		scorer Scorer
+		words string
+		want float64
+	}{
+		{VariedPositions, "cold cord card ward warm", 1},
+		{VariedPositions, "cat cot cut", 0},
+		{VariedPositions, "cat cot cut hut", 0.5},
+		{VariedPositions, "cat cot", 1},
+		{NearDuplicates, "jounce jaunce", -1},
+		{NearDuplicates, "bread broad bro", -1},
+		{NearDuplicates, "cat cot cut", 0},
+		{Criteria{{"varied", VariedPositions, 2}, {"duplicates", NearDuplicates, 3}}, "toun taun tain", 2 - 3 * 2},
+	}
+	for _, c := range cases {
+		if got := c.scorer.Score(strings.Fields(c.words)); got != c.want {
+			t.Errorf("Score(%s), want=%v, got=%v", c.words, c.want, got)
+		}
+	}
+}
+
+func TestBestLadders(t *testing.T) {
+	d, err := Load(strings.NewReader("cold cord card ward warm wold word zzzz"), Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	ctx := context.Background()
+	all := []string{"cold cord card ward warm", "cold cord word ward warm", "cold wold word ward warm"}
+	ladders, err := d.ShortestLadders(ctx, "cold", "warm", MaxRanked, search.Options{})
+	if err != nil || !slices.Equal(joinLadders(ladders), all) {
+		t.Errorf("ShortestLadders(cold, warm), want=%q, got=%q, err=%v", all, joinLadders(ladders), err)
+	}
+	if ladders, _ = d.ShortestLadders(ctx, "cold", "warm", 2, search.Options{}); !slices.Equal(joinLadders(ladders), all[:2]) {
+		t.Errorf("ShortestLadders(limit 2), want=%q, got=%q", all[:2], joinLadders(ladders))
+	}
+	for _, c := range []struct {
+		src, dst string
+		err error
+	}{{"cold", "zzzz", ErrNoPath}, {"cold", "cole", ErrUnknownWord}, {"cold", "cat", ErrLengthMismatch}} {
+		if _, err = d.ShortestLadders(ctx, c.src, c.dst, 1, search.Options{}); !errors.Is(err, c.err) {
+			t.Errorf("ShortestLadders(%s, %s), want %v, got %v", c.src, c.dst, c.err, err)
+		}
+	}
+
+	best := func(scorer Scorer, n int) string {
+		scored, considered, err := d.BestLadders(ctx, "cold", "warm", scorer, n, search.Options{})
+		if err != nil {
+			t.Fatalf("BestLadders(), unexpected error=%v", err)
+		}
+		s := fmt.Sprint(considered)
+		for _, l := range scored {
+			s += fmt.Sprintf("|%s %.2f", strings.Join(l.Words, " "), l.Score)
+		}
+		return s
+	}
+	// without frequencies, every ladder is equally good
+	if got, want := best(d.DefaultCriteria(), 2), "3|cold cord card ward warm 2.00|cold cord word ward warm 2.00"; got != want {
+		t.Errorf("BestLadders(default), want=%q, got=%q", want, got)
+	}
+	wold := ScorerFunc(func(words []string) float64 {
+		if slices.Contains(words, "wold") {
+			return 1
+		}
+		return 0
+	})
+	if got, want := best(wold, 1), "3|cold wold word ward warm 1.00"; got != want {
+		t.Errorf("BestLadders(wold), want=%q, got=%q", want, got)
+	}
+	if err = d.LoadFrequencies(strings.NewReader("word 100\nwold 20\ncard 1")); err != nil {
+		t.Fatalf("LoadFrequencies(), unexpected error=%v", err)
+	}
+	if got, want := best(d.DefaultCriteria(), 3), "3|cold wold word ward warm 4.55|cold cord word ward warm 3.54|cold cord card ward warm 2.23"; got != want {
+		t.Errorf("BestLadders(frequencies), want=%q, got=%q", want, got)
+	}
+}
+
+func joinLadders(ladders [][]string) []string {
+	joined := make([]string, len(ladders))
+	for i, words := range ladders {
+		joined[i] = strings.Join(words, " ")
+	}
+	return joined
+}
+
+// the kinds of step must be read before a word of the ladder can be removed,
+// which go test -race checks
+func TestConcurrentBestLadders(t *testing.T) {
+	d, _ := Load(strings.NewReader("cold cord card ward warm wold word"), Options{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				d.Remove("cord")
+				d.Add("cord")
+			}
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				scored, _, err := d.BestLadders(context.Background(), "cold", "warm", VariedPositions, 3, search.Options{})
+				if err != nil || len(scored[0].Moves) != len(scored[0].Words) - 1 {
+					t.Errorf("BestLadders(), want a ladder with its moves, got=%+v, err=%v", scored, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
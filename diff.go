@@ -0,0 +1,146 @@
+package main
+
+import (
+		"io"
+		"os"
+		"fmt"
+		"context"
+		"strings"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+// the most words, components or ladders listed in each part of a diff
+const maxDiffListed = 20
+
+// a loaded dictionary if one has the given name, and otherwise the
+// dictionary read from the file of that name
+func dictionaryOrPath(name string) (*dictionary.Dictionary, error) {
+	if d := dictionaries[name]; d != nil {
+		return d, nil
+	}
+	return loadPath(name, false, 0)
+}
+
+// compares the dictionaries before and after, each either a loaded
+// dictionary or a file, and, if puzzles is not empty, how the ladders
+// of the pairs listed in that file have changed
+func diffCmd(w io.Writer, before string, after string, puzzles string) error {
+	var pairs []dictionary.Pair
+	if puzzles != "" {
+		file, err := os.Open(puzzles)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		if pairs, err = dictionary.ReadPairs(file); err != nil {
+			return fmt.Errorf("reading %s failed: %w", puzzles, err)
+		}
+	}
+	old, err := dictionaryOrPath(before)
+	if err != nil {
+		return err
+	}
+	updated, err := dictionaryOrPath(after)
+	if err != nil {
+		return err
+	}
+	changes := dictionary.Diff(old, updated)
+	printWords(w, "Added", changes.Added)
+	printWords(w, "Removed", changes.Removed)
+	printComponentChanges(w, "merged, joining words from", changes.Merged)
+	printComponentChanges(w, "split, separating its words into", changes.Split)
+	if puzzles != "" {
+		ladders, err := dictionary.CompareLadders(context.Background(), old, updated, pairs)
+		if err != nil {
+			return err
+		}
+		printLadderChanges(w, puzzles, ladders)
+	}
+	fmt.Fprint(w, "\n")
+	return nil
+}
+
+// writes up to maxDiffListed of words, after a count and verb
+func printWords(w io.Writer, verb string, words []string) {
+	fmt.Fprintf(w, "%s %d %s", verb, len(words), plural(len(words), "word", "words"))
+	if len(words) == 0 {
+		fmt.Fprint(w, ".\n")
+		return
+	}
+	shown := words[:min(len(words), maxDiffListed)]
+	fmt.Fprintf(w, ": %s%s\n", strings.Join(shown, " "), more(len(words) - len(shown)))
+}
+
+func printComponentChanges(w io.Writer, verb string, changes []dictionary.ComponentChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%d %s %s:\n", len(changes), plural(len(changes), "component", "components"), strings.SplitN(verb, ",", 2)[0])
+	for _, c := range changes[:min(len(changes), maxDiffListed)] {
+		parts := make([]string, len(c.Parts))
+		for i, part := range c.Parts {
+			parts[i] = fmt.Sprintf("%s (%d)", part.Word, part.Size)
+		}
+		fmt.Fprintf(w, "  %s (%d words) %s %s\n", c.Component.Word, c.Component.Size, verb, strings.Join(parts, ", "))
+	}
+	if len(changes) > maxDiffListed {
+		fmt.Fprintf(w, " %s\n", more(len(changes) - maxDiffListed))
+	}
+}
+
+// writes the ladders of the puzzles that changed, grouped by how
+func printLadderChanges(w io.Writer, puzzles string, ladders []dictionary.LadderChange) {
+	groups := []struct {
+		title string
+		matches func(l dictionary.LadderChange) bool
+	}{
+		{"became longer", func(l dictionary.LadderChange) bool { return l.Before >= 0 && l.After > l.Before }},
+		{"became shorter", func(l dictionary.LadderChange) bool { return l.After >= 0 && l.After < l.Before }},
+		{"became impossible", func(l dictionary.LadderChange) bool { return l.Before >= 0 && l.After < 0 }},
+		{"became possible", func(l dictionary.LadderChange) bool { return l.Before < 0 && l.After >= 0 }},
+	}
+	fmt.Fprintf(w, "Of the %d puzzles in %s:\n", len(ladders), puzzles)
+	changed := 0
+	for _, g := range groups {
+		var matching []dictionary.LadderChange
+		for _, l := range ladders {
+			if g.matches(l) {
+				matching = append(matching, l)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+		changed += len(matching)
+		fmt.Fprintf(w, "  %d %s:\n", len(matching), g.title)
+		for _, l := range matching[:min(len(matching), maxDiffListed)] {
+			fmt.Fprintf(w, "    %s %s: %s → %s\n", l.Src, l.Dst, stepCount(l.Before), stepCount(l.After))
+		}
+		if len(matching) > maxDiffListed {
+			fmt.Fprintf(w, "    %s\n", more(len(matching) - maxDiffListed))
+		}
+	}
+	fmt.Fprintf(w, "  %d unchanged.\n", len(ladders) - changed)
+}
+
+func stepCount(steps int) string {
+	if steps < 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%d %s", steps, plural(steps, "step", "steps"))
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+// notes how many items were not listed, if any
+func more(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" ... and %d more", n)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+		"testing"
+		"os"
+		"bytes"
+		"strings"
+		"path/filepath"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestDiffCmd(t *testing.T) {
+	defer func(saved map[string]*dictionary.Dictionary) { dictionaries = saved }(dictionaries)
+	dictionaries = make(map[string]*dictionary.Dictionary)
+	for name, words := range map[string]string{
+		"old": "cold cord card ward warm bat cat hat zzz zzy",
+		"new": "cold cord ward warm bat cat hat zzz zzy zat zzt",
+	} {
+		d, err := dictionary.Load(strings.NewReader(words), dictionary.Options{})
+		if err != nil {
+			t.Fatalf("Load(%s), unexpected error=%v", name, err)
+		}
+		dictionaries[name] = d
+	}
+	puzzles := filepath.Join(t.TempDir(), "puzzles.txt")
+	os.WriteFile(puzzles, []byte("cold warm\ncat hat\nbat zzz\ncord card\nbat cat\n"), 0600)
+
+	var out bytes.Buffer
+	if err := diffCmd(&out, "old", "new", puzzles); err != nil {
+		t.Fatalf("diffCmd(), unexpected error=%v", err)
+	}
+	want := "Added 2 words: zat zzt\n" +
+		"Removed 1 word: card\n" +
+		"1 component merged:\n" +
+		"  bat (7 words) merged, joining words from bat (3), zzy (2)\n" +
+		"1 component split:\n" +
+		"  card (5 words) split, separating its words into cold (2), ward (2)\n" +
+		"Of the 5 puzzles in " + puzzles + ":\n" +
+		"  2 became impossible:\n" +
+		"    cold warm: 4 steps → none\n" +
+		"    cord card: 1 step → none\n" +
+		"  1 became possible:\n" +
+		"    bat zzz: none → 3 steps\n" +
+		"  2 unchanged.\n\n"
+	if out.String() != want {
+		t.Errorf("diffCmd(), want:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	out.Reset()
+	diffCmd(&out, "new", "new", "")
+	if want := "Added 0 words.\nRemoved 0 words.\n\n"; out.String() != want {
+		t.Errorf("diffCmd(new, new), want=%q, got=%q", want, out.String())
+	}
+	os.WriteFile(puzzles, []byte("cold warmer\n"), 0600)
+	if err := diffCmd(&out, "old", "new", puzzles); err == nil || !strings.Contains(err.Error(), "pair line 1") {
+		t.Errorf("diffCmd(bad puzzles), want a pair line error, got=%v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+		"io"
+		"os"
+		"fmt"
+		"slices"
+		"context"
+		"strings"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+// the rules, as the introduction to a puzzle book solved with the given
+// kinds of step
+func doubletsRules(moves dictionary.Move) string {
+	const end = "Every step must be a word, and the puzzle shows how few steps it can be done in."
+	if moves == dictionary.MoveSubstitution {
+		return "Turn the first word of each pair into the second by changing one letter at a time. " + end
+	}
+	var steps []string
+	if moves & dictionary.MoveSubstitution != 0 {
+		steps = append(steps, "changes one letter")
+	}
+	if moves & dictionary.MoveTransposition != 0 {
+		steps = append(steps, "swaps two neighbouring letters")
+	}
+	if moves & dictionary.MoveAnagram != 0 {
+		steps = append(steps, "rearranges the letters")
+	}
+	last := len(steps) - 1
+	if last > 0 {
+		steps[last-1] += " or " + steps[last]
+		steps = steps[:last]
+	}
+	return "Turn the first word of each pair into the second one step at a time, where each step " +
+		strings.Join(steps, ", ") + ". " + end
+}
+
+// solves the pairs listed in the file at path and writes them as a puzzle
+// book; args are the options markdown or text (the default), answers,
+// which adds the answers at the end, dictionary=name, which solves them
+// in another loaded dictionary, and out=file, which writes the book to
+// file instead of w
+func doubletsCmd(w io.Writer, path string, args []string) error {
+	markdown, answers := false, false
+	var out, name string
+	for _, arg := range args {
+		key, value, _ := strings.Cut(arg, "=")
+		switch {
+		case arg == "markdown" || arg == "text":
+			markdown = arg == "markdown"
+		case arg == "answers":
+			answers = true
+		case key == "dictionary" && value != "":
+			name = value
+		case key == "out" && value != "":
+			out = value
+		default:
+			return errUsage
+		}
+	}
+	d := dict
+	if name != "" {
+		var err error
+		if d, err = namedDictionary(name); err != nil {
+			return err
+		}
+	}
+	if d == nil {
+		return errNoDictionary
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	pairs, err := dictionary.ReadPairs(file)
+	if err != nil {
+		return fmt.Errorf("reading %s failed: %w", path, err)
+	}
+	doublets, err := d.SolveDoublets(context.Background(), pairs, searchOptions)
+	if err != nil {
+		return err
+	}
+	var solved, unsolved []dictionary.Doublet
+	for _, doublet := range doublets {
+		if doublet.Err == nil {
+			solved = append(solved, doublet)
+		} else {
+			unsolved = append(unsolved, doublet)
+		}
+	}
+
+	book := w
+	if out != "" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		book = f
+	}
+	rules := doubletsRules(d.Moves())
+	if markdown {
+		writeMarkdownBook(book, rules, solved, answers)
+	} else {
+		writeTextBook(book, rules, solved, answers)
+	}
+	if out != "" {
+		fmt.Fprintf(w, "Wrote %d %s to %s.\n", len(solved), plural(len(solved), "puzzle", "puzzles"), out)
+	}
+	if len(unsolved) > 0 {
+		if out == "" {
+			fmt.Fprint(w, "\n")
+		}
+		fmt.Fprintf(w, "Left out %d %s:\n", len(unsolved), plural(len(unsolved), "pair", "pairs"))
+		for _, doublet := range unsolved {
+			fmt.Fprintf(w, "  %s %s: %v\n", doublet.Src, doublet.Dst, doublet.Err)
+		}
+	}
+	fmt.Fprint(w, "\n")
+	return nil
+}
+
+func writeMarkdownBook(w io.Writer, rules string, doublets []dictionary.Doublet, answers bool) {
+	fmt.Fprintf(w, "# Doublets\n\n%s\n\n", rules)
+	for i, doublet := range doublets {
+		fmt.Fprintf(w, "%d. Turn **%s** into **%s** in %d %s.\n", i + 1,
+			doublet.Src, doublet.Dst, doublet.Steps(), plural(doublet.Steps(), "step", "steps"))
+	}
+	if !answers || len(doublets) == 0 {
+		return
+	}
+	fmt.Fprint(w, "\n## Answers\n\n")
+	for i, doublet := range doublets {
+		fmt.Fprintf(w, "%d. %s\n", i + 1, strings.Join(doublet.Ladder.Words, ", "))
+	}
+}
+
+func writeTextBook(w io.Writer, rules string, doublets []dictionary.Doublet, answers bool) {
+	fmt.Fprintf(w, "DOUBLETS\n\n%s\n\n", rules)
+	width := len(fmt.Sprint(len(doublets)))
+	for i, doublet := range doublets {
+		fmt.Fprintf(w, "%*d. %s into %s, in %d %s\n", width, i + 1, strings.ToUpper(doublet.Src),
+			strings.ToUpper(doublet.Dst), doublet.Steps(), plural(doublet.Steps(), "step", "steps"))
+	}
+	if !answers || len(doublets) == 0 {
+		return
+	}
+	fmt.Fprint(w, "\nANSWERS\n\n")
+	for i, doublet := range doublets {
+		words := slices.Clone(doublet.Ladder.Words)
+		words[0], words[len(words)-1] = strings.ToUpper(words[0]), strings.ToUpper(words[len(words)-1])
+		fmt.Fprintf(w, "%*d. %s\n", width, i + 1, strings.Join(words, " - "))
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+		"testing"
+		"os"
+		"bytes"
+		"strings"
+		"path/filepath"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestDoubletsCmd(t *testing.T) {
+	defer func(saved map[string]*dictionary.Dictionary, d *dictionary.Dictionary) { dictionaries, dict = saved, d }(dictionaries, dict)
+	dictionaries = make(map[string]*dictionary.Dictionary)
+	for name, words := range map[string]string{
+		"small": "cold cord card ward warm cat hat",
+		"large": "cold cord card ward warm cat hat cot dot dog",
+	} {
+		d, err := dictionary.Load(strings.NewReader(words), dictionary.Options{})
+		if err != nil {
+			t.Fatalf("Load(%s), unexpected error=%v", name, err)
+		}
+		dictionaries[name] = d
+	}
+	useDictionary("small")
+	dir := t.TempDir()
+	pairs := filepath.Join(dir, "pairs.txt")
+	os.WriteFile(pairs, []byte("# Carroll would approve\ncold warm\ncat hat\ncat dog\n"), 0600)
+
+	var out bytes.Buffer
+	if err := doubletsCmd(&out, pairs, []string{"answers"}); err != nil {
+		t.Fatalf("doubletsCmd(), unexpected error=%v", err)
+	}
+	want := "DOUBLETS\n" +
+		"\n" + doubletsRules(dictionary.MoveSubstitution) + "\n" +
+		"\n" +
+		"1. COLD into WARM, in 4 steps\n" +
+		"2. CAT into HAT, in 1 step\n" +
+		"\n" +
+		"ANSWERS\n" +
+		"\n" +
+		"1. COLD - cord - card - ward - WARM\n" +
+		"2. CAT - HAT\n" +
+		"\n" +
+		"Left out 1 pair:\n" +
+		"  cat dog: unknown word: dog\n" +
+		"\n"
+	if out.String() != want {
+		t.Errorf("doubletsCmd(), want:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	out.Reset()
+	book := filepath.Join(dir, "book.md")
+	if err := doubletsCmd(&out, pairs, []string{"markdown", "dictionary=large", "out=" + book}); err != nil {
+		t.Fatalf("doubletsCmd(markdown), unexpected error=%v", err)
+	}
+	if want = "Wrote 3 puzzles to " + book + ".\n\n"; out.String() != want {
+		t.Errorf("doubletsCmd(markdown), want %q, got %q", want, out.String())
+	}
+	data, _ := os.ReadFile(book)
+	want = "# Doublets\n" +
+		"\n" + doubletsRules(dictionary.MoveSubstitution) + "\n" +
+		"\n" +
+		"1. Turn **cold** into **warm** in 4 steps.\n" +
+		"2. Turn **cat** into **hat** in 1 step.\n" +
+		"3. Turn **cat** into **dog** in 3 steps.\n"
+	if string(data) != want {
+		t.Errorf("doubletsCmd(markdown), wrote:\n%s\nwant:\n%s", data, want)
+	}
+
+	for _, args := range [][]string{{"dictionary=none"}, {"bold"}} {
+		if err := doubletsCmd(&out, pairs, args); err == nil {
+			t.Errorf("doubletsCmd(%v), want error", args)
+		}
+	}
+	if err := doubletsCmd(&out, filepath.Join(dir, "missing.txt"), nil); err == nil {
+		t.Errorf("doubletsCmd(missing.txt), want error")
+	}
+}
+
+func TestDoubletsRules(t *testing.T) {
+	end := " Every step must be a word, and the puzzle shows how few steps it can be done in."
+	cases := []struct {
+		moves dictionary.Move
+		want string
+	}{
+		{dictionary.MoveSubstitution, "Turn the first word of each pair into the second by changing one letter at a time." + end},
+		{dictionary.MoveSubstitution | dictionary.MoveTransposition | dictionary.MoveAnagram,
+			"Turn the first word of each pair into the second one step at a time, where each step changes one letter, " +
+			"swaps two neighbouring letters or rearranges the letters." + end},
+		{dictionary.MoveAnagram, "Turn the first word of each pair into the second one step at a time, " +
+			"where each step rearranges the letters." + end},
+	}
+	for _, c := range cases {
+		if got := doubletsRules(c.moves); got != c.want {
+			t.Errorf("doubletsRules(%v), want=%q, got=%q", c.moves, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package grapher
+
+import (
+		"io"
+		"sort"
+		"sync"
+		"runtime"
+		"strconv"
+		"math/rand"
+		"encoding/csv"
+)
+
+// betweenness and closeness centrality by Brandes' algorithm: a breadth-first
+// search from each source counts the shortest paths to every other word,
+// then walks back from the furthest words accumulating the share of those
+// paths that pass through each word; sampling searches from only some
+// sources and scales the totals up, which estimates both measures
+
+// options for Centralities
+type CentralityOptions = SampleOptions
+
+// how Centralities and LadderLengths choose the words they search from
+type SampleOptions struct {
+	// the number of sources to search from, chosen at random;
+	// zero, or at least the number of words, searches from every word
+	Samples int
+	// seeds the choice of sources, so that estimates can be repeated
+	Seed int64
+	// the number of searches run at once; zero uses runtime.GOMAXPROCS
+	Workers int
+}
+
+// the centrality of one word within a set of words
+type Centrality struct {
+	Node *WordNode
+	// the number of pairs of other words whose shortest ladders pass
+	// through the word, where a pair with several shortest ladders counts
+	// the fraction of them that do
+	Betweenness float64
+	// the reciprocal of the word's mean distance to the others,
+	// or zero if it reaches none of them
+	Closeness float64
+}
+
+// the centrality of each of nodes, which should form a component, considering
+// only ladders among them; ordered by betweenness, highest first, then by
+// closeness and alphabetically; requires a compressed graph
+func Centralities(nodes []*WordNode, opts CentralityOptions) []Centrality {
+	n := len(nodes)
+	adjacent := adjacency(nodes)
+	sources := opts.sources(n)
+	workers := opts.workers(len(sources))
+
+	// each worker takes every workers'th source and keeps its own totals,
+	// which are added in a fixed order so that the result is repeatable
+	totals := make([]*brandes, workers)
+	var done sync.WaitGroup
+	for w := range totals {
+		totals[w] = newBrandes(n)
+		done.Add(1)
+		go func(b *brandes, first int) {
+			defer done.Done()
+			for i := first; i < len(sources); i += workers {
+				b.search(adjacent, sources[i])
+			}
+		}(totals[w], w)
+	}
+	done.Wait()
+
+	// each pair is counted from both ends, and sampling counts a share
+	scale := float64(n) / float64(len(sources))
+	centralities := make([]Centrality, n)
+	for i, node := range nodes {
+		c := Centrality{Node: node}
+		var distances float64
+		for _, b := range totals {
+			c.Betweenness += b.betweenness[i]
+			distances += b.distances[i]
+		}
+		c.Betweenness *= scale / 2
+		if distances > 0 {
+			c.Closeness = float64(n - 1) / (distances * scale)
+		}
+		centralities[i] = c
+	}
+	sort.Slice(centralities, func(i, j int) bool {
+		a, b := centralities[i], centralities[j]
+		switch {
+		case a.Betweenness != b.Betweenness:
+			return a.Betweenness > b.Betweenness
+		case a.Closeness != b.Closeness:
+			return a.Closeness > b.Closeness
+		}
+		return a.Node.Word < b.Node.Word
+	})
+	return centralities
+}
+
+// the neighbours of each of nodes, by index into nodes,
+// leaving out words not in nodes
+func adjacency(nodes []*WordNode) [][]int32 {
+	index := make(map[*WordNode]int32, len(nodes))
+	for i, node := range nodes {
+		index[node] = int32(i)
+	}
+	adjacent := make([][]int32, len(nodes))
+	for i, node := range nodes {
+		for _, neighbour := range node.Neighbours {
+			if j, ok := index[neighbour.(*WordNode)]; ok {
+				adjacent[i] = append(adjacent[i], j)
+			}
+		}
+	}
+	return adjacent
+}
+
+// the indices of the words to search from, of n words
+func (opts SampleOptions) sources(n int) []int {
+	if opts.Samples > 0 && opts.Samples < n {
+		return rand.New(rand.NewSource(opts.Seed)).Perm(n)[:opts.Samples]
+	}
+	sources := make([]int, n)
+	for i := range sources {
+		sources[i] = i
+	}
+	return sources
+}
+
+// the number of workers to share searches from the given number of sources
+func (opts SampleOptions) workers(sources int) int {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return max(min(workers, sources), 1)
+}
+
+// the running totals and scratch space of one worker
+type brandes struct {
+	betweenness []float64
+	distances []float64 // summed from every source searched
+	depth []int32 // -1 if not yet reached
+	paths []float64 // the number of shortest paths from the source
+	dependency []float64
+	order []int32 // in order of discovery
+}
+
+func newBrandes(n int) *brandes {
+	return &brandes{
+		betweenness: make([]float64, n),
+		distances: make([]float64, n),
+		depth: make([]int32, n),
+		paths: make([]float64, n),
+		dependency: make([]float64, n),
+		order: make([]int32, 0, n),
+	}
+}
+
+// adds the paths from source to the totals
+func (b *brandes) search(adjacent [][]int32, source int) {
+	for i := range b.depth {
+		b.depth[i], b.paths[i], b.dependency[i] = -1, 0, 0
+	}
+	b.depth[source], b.paths[source] = 0, 1
+	b.order = append(b.order[:0], int32(source))
+	for head := 0; head < len(b.order); head++ {
+		v := b.order[head]
+		b.distances[v] += float64(b.depth[v])
+		for _, w := range adjacent[v] {
+			if b.depth[w] < 0 {
+				b.depth[w] = b.depth[v] + 1
+				b.order = append(b.order, w)
+			}
+			if b.depth[w] == b.depth[v] + 1 {
+				b.paths[w] += b.paths[v]
+			}
+		}
+	}
+	for i := len(b.order) - 1; i > 0; i-- {
+		w := b.order[i]
+		for _, v := range adjacent[w] {
+			if b.depth[v] == b.depth[w] - 1 {
+				b.dependency[v] += b.paths[v] / b.paths[w] * (1 + b.dependency[w])
+			}
+		}
+		b.betweenness[w] += b.dependency[w]
+	}
+}
+
+// writes centralities as CSV, with a header row of
+// word, betweenness and closeness
+func WriteCentralityCSV(w io.Writer, centralities []Centrality) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"word", "betweenness", "closeness"})
+	for _, c := range centralities {
+		out.Write([]string{
+			c.Node.Word,
+			strconv.FormatFloat(c.Betweenness, 'g', -1, 64),
+			strconv.FormatFloat(c.Closeness, 'g', -1, 64),
+		})
+	}
+	out.Flush()
+	return out.Error()
+}
+
@@ -0,0 +1,84 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"bytes"
+		"fmt"
+		"math"
+)
+
+func centralityWords(centralities []Centrality) string {
+	s := make([]string, len(centralities))
+	for i, c := range centralities {
+		s[i] = fmt.Sprintf("%s %.3g %.3g", c.Node.Word, c.Betweenness, c.Closeness)
+	}
+	return strings.Join(s, "|")
+}
+
+func TestCentralities(t *testing.T) {
+	cases := []struct {
+		words string
+		want string
+	}{
+		// a path
+		{"cold cord card ward warm", "card 4 0.667|cord 3 0.571|ward 3 0.571|cold 0 0.4|warm 0 0.4"},
+		// a cycle, in which opposite words are joined by two ladders
+		{"cab cat cot cob", "cab 0.5 0.75|cat 0.5 0.75|cob 0.5 0.75|cot 0.5 0.75"},
+		// a clique, in which every word is next to every other
+		{"cat bat hat mat", "bat 0 1|cat 0 1|hat 0 1|mat 0 1"},
+		{"zzz", "zzz 0 0"},
+	}
+	for _, c := range cases {
+		graph, _, err := ScanLinkCompress(strings.NewReader(c.words))
+		if err != nil {
+			t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+		}
+		for workers := 1; workers <= 3; workers++ {
+			nodes := graph.Bucket(len(strings.Fields(c.words)[0]))
+			got := centralityWords(Centralities(nodes, CentralityOptions{Workers: workers}))
+			if got != c.want {
+				t.Errorf("Centralities(%s, workers=%d), want=%q, got=%q", c.words, workers, c.want, got)
+			}
+		}
+	}
+}
+
+func TestCentralitiesSampled(t *testing.T) {
+	graph, _, err := ScanLinkCompress(strings.NewReader(
+		"bat cat hat mat rat cot cog dog dig big bag bad bid bud mud mad had ham him hum"))
+	if err != nil {
+		t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+	}
+	nodes := Component(graph[3]["cat"])
+	exact := Centralities(nodes, CentralityOptions{})
+	if all := Centralities(nodes, CentralityOptions{Samples: len(nodes), Seed: 3}); centralityWords(all) != centralityWords(exact) {
+		t.Errorf("Centralities(every sample), want the exact result")
+	}
+	a := Centralities(nodes, CentralityOptions{Samples: 8, Seed: 1, Workers: 2})
+	b := Centralities(nodes, CentralityOptions{Samples: 8, Seed: 1, Workers: 2})
+	if centralityWords(a) != centralityWords(b) {
+		t.Errorf("Centralities(seed 1), want repeatable results")
+	}
+	// the estimates total about as much as the exact values
+	var exactTotal, sampledTotal float64
+	for i := range exact {
+		exactTotal += exact[i].Betweenness
+		sampledTotal += a[i].Betweenness
+	}
+	if math.Abs(sampledTotal - exactTotal) > exactTotal / 2 {
+		t.Errorf("Centralities(8 samples), total betweenness %.1f, want near %.1f", sampledTotal, exactTotal)
+	}
+}
+
+func TestWriteCentralityCSV(t *testing.T) {
+	graph, _, _ := ScanLinkCompress(strings.NewReader("cold cord card"))
+	var out bytes.Buffer
+	if err := WriteCentralityCSV(&out, Centralities(graph.Bucket(4), CentralityOptions{})); err != nil {
+		t.Fatalf("WriteCentralityCSV(), unexpected error=%v", err)
+	}
+	want := "word,betweenness,closeness\ncord,1,1\ncard,0,0.6666666666666666\ncold,0,0.6666666666666666\n"
+	if out.String() != want {
+		t.Errorf("WriteCentralityCSV(), want=%q, got=%q", want, out.String())
+	}
+}
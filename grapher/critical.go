@@ -0,0 +1,139 @@
+package grapher
+
+import (
+		"sort"
+)
+
+// finding the words and edges whose removal would disconnect part of a
+// bucket, by Tarjan's depth-first search: a node's low point is the
+// earliest discovered node reachable from its subtree by one back edge,
+// and a child whose low point is not before its parent is cut off from
+// the rest of the graph by removing that parent
+
+// a word whose removal divides its component into Pieces,
+// the sizes of which are ordered largest first
+type Articulation struct {
+	Node *WordNode
+	Pieces []int
+}
+
+// an edge whose removal divides its component in two, of sizes
+// Pieces[0] on A's side and Pieces[1] on B's side, with A.Word < B.Word
+type Bridge struct {
+	A, B *WordNode
+	Pieces [2]int
+}
+
+// the number of words separated from the largest piece
+func (a Articulation) Cut() int {
+	cut := 0
+	for _, size := range a.Pieces[1:] {
+		cut += size
+	}
+	return cut
+}
+
+// the size of the smaller piece
+func (b Bridge) Cut() int {
+	return min(b.Pieces[0], b.Pieces[1])
+}
+
+// the articulation points and bridges among the words of a given length,
+// ordered by the number of words they cut off, most first, then
+// alphabetically; words whose removal only lengthens ladders are not
+// among them; requires a compressed graph
+func (graph WordGraph) Critical(letterCount int) (articulations []Articulation, bridges []Bridge) {
+	nodes := graph.Bucket(letterCount)
+	discovered := make(map[*WordNode]int, len(nodes)) // order of discovery, from 1
+	low := make(map[*WordNode]int, len(nodes))
+	size := make(map[*WordNode]int, len(nodes)) // of each node's subtree
+	order := 0
+	// the pieces separated below each node, before the rest of its
+	// component is known
+	separated := make(map[*WordNode][]int)
+	type treeEdge struct {
+		parent, child *WordNode
+	}
+	var cuts []treeEdge // tree edges that are bridges
+	var visited []*WordNode // in order of discovery
+
+	var visit func(node, parent *WordNode)
+	visit = func(node, parent *WordNode) {
+		order++
+		discovered[node], low[node], size[node] = order, order, 1
+		visited = append(visited, node)
+		skippedParent := false
+		for _, neighbour := range node.Neighbours {
+			n := neighbour.(*WordNode)
+			if n == parent && !skippedParent {
+				skippedParent = true // the tree edge, not a back edge
+				continue
+			}
+			if discovered[n] != 0 {
+				low[node] = min(low[node], discovered[n])
+				continue
+			}
+			visit(n, node)
+			size[node] += size[n]
+			low[node] = min(low[node], low[n])
+			if low[n] >= discovered[node] {
+				separated[node] = append(separated[node], size[n])
+			}
+			if low[n] > discovered[node] {
+				cuts = append(cuts, treeEdge{node, n})
+			}
+		}
+	}
+	for _, root := range nodes {
+		if discovered[root] != 0 {
+			continue
+		}
+		first, start := len(cuts), len(visited)
+		visit(root, nil)
+		componentSize := len(visited) - start
+		for _, node := range visited[start:] {
+			pieces := separated[node]
+			if len(pieces) == 0 {
+				continue
+			}
+			rest := componentSize - 1
+			for _, piece := range pieces {
+				rest -= piece
+			}
+			if rest > 0 {
+				pieces = append(pieces, rest)
+			}
+			if len(pieces) < 2 {
+				continue // the root of a tree with one subtree
+			}
+			sort.Sort(sort.Reverse(sort.IntSlice(pieces)))
+			articulations = append(articulations, Articulation{node, pieces})
+		}
+		for _, edge := range cuts[first:] {
+			below := size[edge.child]
+			b := Bridge{edge.parent, edge.child, [2]int{componentSize - below, below}}
+			if b.B.Word < b.A.Word {
+				b.A, b.B, b.Pieces[0], b.Pieces[1] = b.B, b.A, b.Pieces[1], b.Pieces[0]
+			}
+			bridges = append(bridges, b)
+		}
+	}
+	sort.Slice(articulations, func(i, j int) bool {
+		a, b := articulations[i], articulations[j]
+		if a.Cut() != b.Cut() {
+			return a.Cut() > b.Cut()
+		}
+		return a.Node.Word < b.Node.Word
+	})
+	sort.Slice(bridges, func(i, j int) bool {
+		a, b := bridges[i], bridges[j]
+		if a.Cut() != b.Cut() {
+			return a.Cut() > b.Cut()
+		}
+		if a.A.Word != b.A.Word {
+			return a.A.Word < b.A.Word
+		}
+		return a.B.Word < b.B.Word
+	})
+	return
+}
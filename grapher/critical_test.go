@@ -0,0 +1,116 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"fmt"
+)
+
+func TestCritical(t *testing.T) {
+	// the cycle cab-cat-cot-cog-cob-cab, with hat hanging from cat,
+	// dog from cog, and an isolated zzz
+	graph, _, err := ScanLinkCompress(strings.NewReader("cat cot cog dog hat zzz cab cob"))
+	if err != nil {
+		t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+	}
+	articulations, bridges := graph.Critical(3)
+	var got []string
+	for _, a := range articulations {
+		got = append(got, fmt.Sprint(a.Node.Word, a.Pieces, a.Cut()))
+	}
+	if want := "cat[5 1] 1|cog[5 1] 1"; strings.Join(got, "|") != want {
+		t.Errorf("Critical() articulations, want=%q, got=%q", want, strings.Join(got, "|"))
+	}
+	got = nil
+	for _, b := range bridges {
+		got = append(got, fmt.Sprint(b.A.Word, "-", b.B.Word, b.Pieces, b.Cut()))
+	}
+	if want := "cat-hat[6 1] 1|cog-dog[6 1] 1"; strings.Join(got, "|") != want {
+		t.Errorf("Critical() bridges, want=%q, got=%q", want, strings.Join(got, "|"))
+	}
+	// a path: each inner word and every edge is critical
+	graph, _, _ = ScanLinkCompress(strings.NewReader("cold cord card ward warm"))
+	articulations, bridges = graph.Critical(4)
+	got = nil
+	for _, a := range articulations {
+		got = append(got, fmt.Sprint(a.Node.Word, a.Pieces))
+	}
+	for _, b := range bridges {
+		got = append(got, fmt.Sprint(b.A.Word, "-", b.B.Word, b.Pieces))
+	}
+	if want := "card[2 2]|cord[3 1]|ward[3 1]|card-cord[3 2]|card-ward[3 2]|cold-cord[1 4]|ward-warm[4 1]"; strings.Join(got, "|") != want {
+		t.Errorf("Critical(path), want=%q, got=%q", want, strings.Join(got, "|"))
+	}
+	if a, b := graph.Critical(3); a != nil || b != nil {
+		t.Errorf("Critical(4), want nothing, got=%v %v", a, b)
+	}
+}
+
+// compares Critical with removing each word and edge in turn
+func TestCriticalBruteForce(t *testing.T) {
+	graph, _, err := ScanLinkCompress(strings.NewReader(
+		"bat cat hat mat rat cot cog dog dig big bag bad bid bud mud mad had ham him hum"))
+	if err != nil {
+		t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+	}
+	articulations, bridges := graph.Critical(3)
+	isArticulation := make(map[string]bool)
+	for _, a := range articulations {
+		isArticulation[a.Node.Word] = true
+	}
+	isBridge := make(map[string]bool)
+	for _, b := range bridges {
+		isBridge[b.A.Word + "-" + b.B.Word] = true
+	}
+	// the number of components, without the node or edge given
+	components := func(without *WordNode, edgeA, edgeB *WordNode) int {
+		seen := map[*WordNode]bool{without: true}
+		count := 0
+		for _, node := range graph.Bucket(3) {
+			if seen[node] {
+				continue
+			}
+			count++
+			stack := []*WordNode{node}
+			seen[node] = true
+			for len(stack) > 0 {
+				current := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				for _, neighbour := range current.Neighbours {
+					n := neighbour.(*WordNode)
+					if seen[n] || (current == edgeA && n == edgeB) || (current == edgeB && n == edgeA) {
+						continue
+					}
+					seen[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+		return count
+	}
+	base := components(nil, nil, nil)
+	for _, node := range graph.Bucket(3) {
+		// removing a word also removes its own component if it was alone
+		want := components(node, nil, nil) > base - boolInt(node.Degree() == 0)
+		if isArticulation[node.Word] != want {
+			t.Errorf("Critical(), articulation %s, want=%v", node.Word, want)
+		}
+		for _, neighbour := range node.Neighbours {
+			n := neighbour.(*WordNode)
+			if n.Word < node.Word {
+				continue
+			}
+			want := components(nil, node, n) > base
+			if isBridge[node.Word + "-" + n.Word] != want {
+				t.Errorf("Critical(), bridge %s-%s, want=%v", node.Word, n.Word, want)
+			}
+		}
+	}
+}
+
+func boolInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,147 @@
+package grapher
+
+import (
+		"io"
+		"sync"
+		"strconv"
+		"math"
+		"encoding/csv"
+)
+
+// the lengths of the shortest ladders between words, for judging how hard
+// puzzles of a length tend to be
+type Distribution struct {
+	// the number of pairs of words whose shortest ladder takes each number
+	// of steps, indexed by steps, so that Counts[0] is zero; estimates are
+	// not whole numbers
+	Counts []float64
+	// the number of pairs of words joined by no ladder
+	Unreachable float64
+	// the number of words searched from, and of words that might have been;
+	// the distribution is exact if they are equal
+	Sources, Words int
+}
+
+func (d Distribution) Exact() bool {
+	return d.Sources == d.Words
+}
+
+// the number of pairs joined by a ladder
+func (d Distribution) Pairs() (pairs float64) {
+	for _, count := range d.Counts {
+		pairs += count
+	}
+	return
+}
+
+// the mean number of steps of the ladders, or zero if there are none
+func (d Distribution) Mean() float64 {
+	var steps float64
+	for length, count := range d.Counts {
+		steps += float64(length) * count
+	}
+	if pairs := d.Pairs(); pairs > 0 {
+		return steps / pairs
+	}
+	return 0
+}
+
+// the lengths of the shortest ladders from node to every other word
+// of its length
+func (graph WordGraph) LadderLengthsFrom(node *WordNode) Distribution {
+	shells := Shells(node, -1)
+	d := Distribution{Counts: make([]float64, len(shells)), Sources: 1, Words: 1}
+	reached := 0
+	for steps, shell := range shells[1:] {
+		d.Counts[steps + 1] = float64(len(shell))
+		reached += len(shell)
+	}
+	d.Unreachable = float64(len(graph[len(node.Word)]) - 1 - reached)
+	return d
+}
+
+// the lengths of the shortest ladders between every pair of words of the
+// given length, each pair counted once; if opts.Samples is fewer than the
+// words, they are estimated from searches from a sample of them
+func (graph WordGraph) LadderLengths(letterCount int, opts SampleOptions) Distribution {
+	nodes := graph.Bucket(letterCount)
+	n := len(nodes)
+	adjacent := adjacency(nodes)
+	sources := opts.sources(n)
+	workers := opts.workers(len(sources))
+
+	// each worker counts the ladders from its share of the sources, and the
+	// counts are added in a fixed order, as by Centralities
+	totals := make([]Distribution, workers)
+	var done sync.WaitGroup
+	for w := range totals {
+		done.Add(1)
+		go func(total *Distribution, first int) {
+			defer done.Done()
+			depth := make([]int32, n)
+			queue := make([]int32, 0, n)
+			for i := first; i < len(sources); i += workers {
+				for j := range depth {
+					depth[j] = -1
+				}
+				depth[sources[i]] = 0
+				queue = append(queue[:0], int32(sources[i]))
+				for head := 0; head < len(queue); head++ {
+					v := queue[head]
+					for _, u := range adjacent[v] {
+						if depth[u] < 0 {
+							depth[u] = depth[v] + 1
+							queue = append(queue, u)
+						}
+					}
+				}
+				total.add(int(depth[queue[len(queue)-1]]), 0)
+				for _, v := range queue[1:] {
+					total.Counts[depth[v]]++
+				}
+				total.Unreachable += float64(n - len(queue))
+			}
+		}(&totals[w], w)
+	}
+	done.Wait()
+
+	// each pair is found from both ends, and sampling finds a share of them
+	d := Distribution{Counts: []float64{0}, Sources: len(sources), Words: n}
+	scale := float64(n) / float64(max(len(sources), 1)) / 2
+	for _, total := range totals {
+		for steps, count := range total.Counts {
+			d.add(steps, count * scale)
+		}
+		d.Unreachable += total.Unreachable * scale
+	}
+	return d
+}
+
+// adds count to Counts[steps], lengthening Counts as needed
+func (d *Distribution) add(steps int, count float64) {
+	for len(d.Counts) <= steps {
+		d.Counts = append(d.Counts, 0)
+	}
+	d.Counts[steps] += count
+}
+
+// writes d as CSV, with a header row of steps and pairs, and a last row
+// for the pairs with no ladder, whose steps are "none"
+func WriteDistributionCSV(w io.Writer, d Distribution) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"steps", "pairs"})
+	for steps, count := range d.Counts {
+		if steps > 0 {
+			out.Write([]string{strconv.Itoa(steps), formatFloat(count)})
+		}
+	}
+	out.Write([]string{"none", formatFloat(d.Unreachable)})
+	out.Flush()
+	return out.Error()
+}
+
+// x to six decimal places, without trailing zeros or an exponent,
+// so that estimates read cleanly in a spreadsheet
+func formatFloat(x float64) string {
+	return strconv.FormatFloat(math.Round(x * 1e6) / 1e6, 'f', -1, 64)
+}
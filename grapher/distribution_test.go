@@ -0,0 +1,60 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"bytes"
+		"fmt"
+		"math"
+)
+
+func TestLadderLengths(t *testing.T) {
+	graph, _, err := ScanLinkCompress(strings.NewReader("cold cord card ward warm zzzz"))
+	if err != nil {
+		t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+	}
+	from := graph.LadderLengthsFrom(graph[4]["cold"])
+	if got := fmt.Sprint(from.Counts, from.Unreachable, from.Exact()); got != "[0 1 1 1 1] 1 true" {
+		t.Errorf("LadderLengthsFrom(cold), got %s", got)
+	}
+	for workers := 1; workers <= 3; workers++ {
+		d := graph.LadderLengths(4, SampleOptions{Workers: workers})
+		if got := fmt.Sprint(d.Counts, d.Unreachable, d.Pairs(), d.Mean(), d.Exact()); got != "[0 4 3 2 1] 5 10 2 true" {
+			t.Errorf("LadderLengths(4, workers=%d), got %s", workers, got)
+		}
+	}
+	if d := graph.LadderLengths(5, SampleOptions{}); d.Pairs() != 0 || d.Mean() != 0 || d.Words != 0 {
+		t.Errorf("LadderLengths(5), want nothing, got %+v", d)
+	}
+}
+
+func TestLadderLengthsSampled(t *testing.T) {
+	graph, _, err := ScanLinkCompress(strings.NewReader(
+		"bat cat hat mat rat cot cog dog dig big bag bad bid bud mud mad had ham him hum zzz yyy"))
+	if err != nil {
+		t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+	}
+	exact := graph.LadderLengths(3, SampleOptions{})
+	if all := graph.LadderLengths(3, SampleOptions{Samples: 22, Seed: 4}); fmt.Sprint(all) != fmt.Sprint(exact) {
+		t.Errorf("LadderLengths(every sample), want %v, got %v", exact, all)
+	}
+	a := graph.LadderLengths(3, SampleOptions{Samples: 6, Seed: 2, Workers: 2})
+	b := graph.LadderLengths(3, SampleOptions{Samples: 6, Seed: 2, Workers: 2})
+	if fmt.Sprint(a) != fmt.Sprint(b) || a.Exact() || a.Sources != 6 {
+		t.Errorf("LadderLengths(seed 2), want repeatable estimates, got %v and %v", a, b)
+	}
+	// every word is counted against every other, reachable or not
+	if total := a.Pairs() + a.Unreachable; math.Abs(total - 22 * 21 / 2) > 1e-9 {
+		t.Errorf("LadderLengths(6 samples), %v pairs in total, want 231", total)
+	}
+}
+
+func TestWriteDistributionCSV(t *testing.T) {
+	var out bytes.Buffer
+	if err := WriteDistributionCSV(&out, Distribution{Counts: []float64{0, 4, 2.5}, Unreachable: 1}); err != nil {
+		t.Fatalf("WriteDistributionCSV(), unexpected error=%v", err)
+	}
+	if want := "steps,pairs\n1,4\n2,2.5\nnone,1\n"; out.String() != want {
+		t.Errorf("WriteDistributionCSV(), want=%q, got=%q", want, out.String())
+	}
+}
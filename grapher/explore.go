@@ -0,0 +1,102 @@
+package grapher
+
+import (
+		"sort"
+)
+
+// queries for exploring the graph around a word, such as why it is
+// isolated or poorly connected; all require a compressed graph
+
+// the number of words one step from node
+func (node *WordNode) Degree() int {
+	return len(node.Neighbours)
+}
+
+// the words one step from node, in alphabetical order
+func (node *WordNode) SortedNeighbours() []*WordNode {
+	nodes := make([]*WordNode, len(node.Neighbours))
+	for i, v := range node.Neighbours {
+		nodes[i] = v.(*WordNode)
+	}
+	sortNodes(nodes)
+	return nodes
+}
+
+// the words within radius steps of node, grouped by distance:
+// shells[d] holds the words exactly d steps away, in alphabetical order,
+// with shells[0] holding node itself; the result ends at the last
+// non-empty shell, and a negative radius is unbounded
+func Shells(node *WordNode, radius int) (shells [][]*WordNode) {
+	seen := map[*WordNode]bool{node: true}
+	frontier := []*WordNode{node}
+	for depth := 0; len(frontier) > 0; depth++ {
+		sortNodes(frontier)
+		shells = append(shells, frontier)
+		if radius >= 0 && depth >= radius {
+			break
+		}
+		var next []*WordNode
+		for _, current := range frontier {
+			for _, neighbour := range current.Neighbours {
+				n := neighbour.(*WordNode)
+				if !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		frontier = next
+	}
+	return
+}
+
+// a summary of the degrees of the words of one length
+type Degrees struct {
+	Words int
+	Isolated int // words with no neighbours
+	Max int
+	Mean float64
+}
+
+// the degrees of the words of a given length
+func (graph WordGraph) Degrees(letterCount int) (d Degrees) {
+	total := 0
+	for _, node := range graph[letterCount] {
+		degree := node.Degree()
+		d.Words++
+		total += degree
+		d.Max = max(d.Max, degree)
+		if degree == 0 {
+			d.Isolated++
+		}
+	}
+	if d.Words > 0 {
+		d.Mean = float64(total) / float64(d.Words)
+	}
+	return
+}
+
+// the connected components of the graph, each in alphabetical order;
+// shorter words come first, and components of the same length are ordered
+// by their first words
+func (graph WordGraph) Components() (components [][]*WordNode) {
+	lengths := make([]int, 0, len(graph))
+	for letterCount := range graph {
+		lengths = append(lengths, letterCount)
+	}
+	sort.Ints(lengths)
+	for _, letterCount := range lengths {
+		seen := make(map[*WordNode]bool, len(graph[letterCount]))
+		for _, node := range graph.Bucket(letterCount) {
+			if seen[node] {
+				continue
+			}
+			component := Component(node)
+			for _, n := range component {
+				seen[n] = true
+			}
+			components = append(components, component)
+		}
+	}
+	return
+}
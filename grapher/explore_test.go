@@ -0,0 +1,55 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+)
+
+func shellWords(shells [][]*WordNode) string {
+	words := make([]string, len(shells))
+	for i, shell := range shells {
+		words[i] = nodeWords(shell)
+	}
+	return strings.Join(words, " | ")
+}
+
+func TestExplore(t *testing.T) {
+	graph := exportTestGraph(t)
+	shells := []struct {
+		word string
+		radius int
+		want string
+	}{
+		{"cot", 0, "cot"},
+		{"cot", 1, "cot | cat cog"},
+		{"cot", 2, "cot | cat cog | dog hat"},
+		{"cot", 5, "cot | cat cog | dog hat"},
+		{"hat", -1, "hat | cat | cot | cog | dog"},
+		{"zzz", 3, "zzz"},
+	}
+	for _, c := range shells {
+		if got := shellWords(Shells(graph[3][c.word], c.radius)); got != c.want {
+			t.Errorf("Shells(%s, %d), want=%q, got=%q", c.word, c.radius, c.want, got)
+		}
+	}
+	if got := nodeWords(graph[3]["cot"].SortedNeighbours()); got != "cat cog" {
+		t.Errorf("SortedNeighbours(cot), want=%q, got=%q", "cat cog", got)
+	}
+	if got := graph[3]["cat"].Degree(); got != 2 {
+		t.Errorf("Degree(cat), want=2, got=%d", got)
+	}
+	want := Degrees{Words: 6, Isolated: 1, Max: 2, Mean: 8.0 / 6}
+	if got := graph.Degrees(3); got != want {
+		t.Errorf("Degrees(3), want=%+v, got=%+v", want, got)
+	}
+	var components []string
+	for _, component := range graph.Components() {
+		components = append(components, nodeWords(component))
+	}
+	if got, want := strings.Join(components, " | "), "cat cog cot dog hat | zzz"; got != want {
+		t.Errorf("Components(), want=%q, got=%q", want, got)
+	}
+	if got := graph.Degrees(9); got != (Degrees{}) {
+		t.Errorf("Degrees(9), want zero, got=%+v", got)
+	}
+}
@@ -0,0 +1,172 @@
+/*
+Exporters for rendering parts of a WordGraph in external tools:
+Graphviz DOT (https://graphviz.org/doc/info/lang.html) and
+GraphML (http://graphml.graphdrawing.org/), as read by Gephi.
+*/
+package grapher
+
+import (
+		"io"
+		"fmt"
+		"sort"
+		"bufio"
+		"strconv"
+		"strings"
+		"encoding/xml"
+)
+
+// a selection of words to export;
+// edges are written between every pair of selected words that are linked,
+// and the edges between consecutive Ladder words are highlighted
+type Export struct {
+	Name string
+	Nodes []*WordNode
+	Ladder []*WordNode
+}
+
+// an undirected edge between two selected words, with a < b
+type exportEdge struct {
+	a, b string
+	highlight bool
+}
+
+// all the words of a given length, in alphabetical order;
+// nil if there are none
+func (graph WordGraph) Bucket(letterCount int) []*WordNode {
+	return sortedNodes(graph[letterCount])
+}
+
+// the nodes of a sub-graph in alphabetical order
+func sortedNodes(subGraph map[string]*WordNode) (nodes []*WordNode) {
+	for _, node := range subGraph {
+		nodes = append(nodes, node)
+	}
+	sortNodes(nodes)
+	return
+}
+
+// all the words reachable from node, including node itself,
+// in alphabetical order; requires a compressed graph
+func Component(node *WordNode) []*WordNode {
+	return Neighbourhood(node, -1)
+}
+
+// all the words within radius transformations of node,
+// including node itself, in alphabetical order;
+// a negative radius is unbounded; requires a compressed graph
+func Neighbourhood(node *WordNode, radius int) (nodes []*WordNode) {
+	for _, shell := range Shells(node, radius) {
+		nodes = append(nodes, shell...)
+	}
+	sortNodes(nodes)
+	return
+}
+
+func sortNodes(nodes []*WordNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Word < nodes[j].Word })
+}
+
+// collects the edges among the selected words, sorted for stable output
+func (e Export) edges() (edges []exportEdge) {
+	selected := make(map[*WordNode]bool, len(e.Nodes))
+	for _, node := range e.Nodes {
+		selected[node] = true
+	}
+	highlighted := make(map[[2]string]bool)
+	for i := 1; i < len(e.Ladder); i++ {
+		a, b := e.Ladder[i-1].Word, e.Ladder[i].Word
+		if b < a {
+			a, b = b, a
+		}
+		highlighted[[2]string{a, b}] = true
+	}
+	for _, node := range e.Nodes {
+		for _, neighbour := range node.Neighbours {
+			n := neighbour.(*WordNode)
+			if !selected[n] || n.Word <= node.Word {
+				continue // each undirected edge is written once
+			}
+			edge := exportEdge{a: node.Word, b: n.Word}
+			edge.highlight = highlighted[[2]string{edge.a, edge.b}]
+			edges = append(edges, edge)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].a != edges[j].a {
+			return edges[i].a < edges[j].a
+		}
+		return edges[i].b < edges[j].b
+	})
+	return
+}
+
+// the set of words on the ladder
+func (e Export) onLadder() map[*WordNode]bool {
+	on := make(map[*WordNode]bool, len(e.Ladder))
+	for _, node := range e.Ladder {
+		on[node] = true
+	}
+	return on
+}
+
+// writes the selection as an undirected Graphviz graph;
+// ladder words and edges are drawn in red
+func WriteDOT(w io.Writer, e Export) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "graph %s {\n", strconv.Quote(e.Name))
+	onLadder := e.onLadder()
+	for _, node := range e.Nodes {
+		if onLadder[node] {
+			fmt.Fprintf(out, "\t%s [color=red, fontcolor=red];\n", strconv.Quote(node.Word))
+		} else {
+			fmt.Fprintf(out, "\t%s;\n", strconv.Quote(node.Word))
+		}
+	}
+	for _, edge := range e.edges() {
+		if edge.highlight {
+			fmt.Fprintf(out, "\t%s -- %s [color=red, penwidth=3];\n", strconv.Quote(edge.a), strconv.Quote(edge.b))
+		} else {
+			fmt.Fprintf(out, "\t%s -- %s;\n", strconv.Quote(edge.a), strconv.Quote(edge.b))
+		}
+	}
+	fmt.Fprint(out, "}\n")
+	return out.Flush()
+}
+
+// writes the selection as an undirected GraphML graph;
+// ladder words and edges carry ladder=true
+func WriteGraphML(w io.Writer, e Export) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprint(out, xml.Header)
+	fmt.Fprint(out, "<graphml xmlns=\"http://graphml.graphdrawing.org/xmlns\">\n")
+	fmt.Fprint(out, "\t<key id=\"word\" for=\"node\" attr.name=\"word\" attr.type=\"string\"/>\n")
+	fmt.Fprint(out, "\t<key id=\"nladder\" for=\"node\" attr.name=\"ladder\" attr.type=\"boolean\">" +
+		"<default>false</default></key>\n")
+	fmt.Fprint(out, "\t<key id=\"eladder\" for=\"edge\" attr.name=\"ladder\" attr.type=\"boolean\">" +
+		"<default>false</default></key>\n")
+	fmt.Fprintf(out, "\t<graph id=\"%s\" edgedefault=\"undirected\">\n", escapeXML(e.Name))
+	onLadder := e.onLadder()
+	for _, node := range e.Nodes {
+		word := escapeXML(node.Word)
+		fmt.Fprintf(out, "\t\t<node id=\"%s\"><data key=\"word\">%s</data>", word, word)
+		if onLadder[node] {
+			fmt.Fprint(out, "<data key=\"nladder\">true</data>")
+		}
+		fmt.Fprint(out, "</node>\n")
+	}
+	for _, edge := range e.edges() {
+		fmt.Fprintf(out, "\t\t<edge source=\"%s\" target=\"%s\">", escapeXML(edge.a), escapeXML(edge.b))
+		if edge.highlight {
+			fmt.Fprint(out, "<data key=\"eladder\">true</data>")
+		}
+		fmt.Fprint(out, "</edge>\n")
+	}
+	fmt.Fprint(out, "\t</graph>\n</graphml>\n")
+	return out.Flush()
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
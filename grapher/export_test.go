@@ -0,0 +1,99 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"bytes"
+)
+
+// returns a compressed graph of: cat-cot-cog-dog, hat-cat, and an isolated zzz
+func exportTestGraph(t *testing.T) WordGraph {
+	graph, _, err := ScanLinkCompress(strings.NewReader("cat cot cog dog hat zzz"))
+	if err != nil {
+		t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+	}
+	return graph
+}
+
+func nodeWords(nodes []*WordNode) string {
+	words := make([]string, len(nodes))
+	for i, node := range nodes {
+		words[i] = node.Word
+	}
+	return strings.Join(words, " ")
+}
+
+func TestSelections(t *testing.T) {
+	graph := exportTestGraph(t)
+	cases := []struct {
+		name string
+		got []*WordNode
+		want string
+	}{
+		{"bucket", graph.Bucket(3), "cat cog cot dog hat zzz"},
+		{"missing bucket", graph.Bucket(7), ""},
+		{"component", Component(graph[3]["dog"]), "cat cog cot dog hat"},
+		{"isolated component", Component(graph[3]["zzz"]), "zzz"},
+		{"radius 0", Neighbourhood(graph[3]["cot"], 0), "cot"},
+		{"radius 1", Neighbourhood(graph[3]["cot"], 1), "cat cog cot"},
+		{"radius 2", Neighbourhood(graph[3]["cot"], 2), "cat cog cot dog hat"},
+	}
+	for _, c := range cases {
+		if got := nodeWords(c.got); got != c.want {
+			t.Errorf("TestSelections(%s), want=%q, got=%q", c.name, c.want, got)
+		}
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	graph := exportTestGraph(t)
+	ladder := []*WordNode{graph[3]["cat"], graph[3]["cot"], graph[3]["cog"]}
+	export := Export{
+		Name: "test",
+		Nodes: Neighbourhood(graph[3]["cot"], 1),
+		Ladder: ladder,
+	}
+	var out bytes.Buffer
+	if err := WriteDOT(&out, export); err != nil {
+		t.Fatalf("WriteDOT(), unexpected error=%v", err)
+	}
+	want := "graph \"test\" {\n" +
+		"\t\"cat\" [color=red, fontcolor=red];\n" +
+		"\t\"cog\" [color=red, fontcolor=red];\n" +
+		"\t\"cot\" [color=red, fontcolor=red];\n" +
+		"\t\"cat\" -- \"cot\" [color=red, penwidth=3];\n" +
+		"\t\"cog\" -- \"cot\" [color=red, penwidth=3];\n" +
+		"}\n"
+	if out.String() != want {
+		t.Errorf("WriteDOT(), want=%q, got=%q", want, out.String())
+	}
+}
+
+func TestWriteGraphML(t *testing.T) {
+	graph := exportTestGraph(t)
+	export := Export{
+		Name: "a<b",
+		Nodes: []*WordNode{graph[3]["cog"], graph[3]["cot"], graph[3]["dog"]},
+		Ladder: []*WordNode{graph[3]["cot"], graph[3]["cog"]},
+	}
+	var out bytes.Buffer
+	if err := WriteGraphML(&out, export); err != nil {
+		t.Fatalf("WriteGraphML(), unexpected error=%v", err)
+	}
+	got := out.String()
+	wants := []string{
+		"<graph id=\"a&lt;b\" edgedefault=\"undirected\">",
+		"<node id=\"cot\"><data key=\"word\">cot</data><data key=\"nladder\">true</data></node>",
+		"<node id=\"dog\"><data key=\"word\">dog</data></node>",
+		"<edge source=\"cog\" target=\"cot\"><data key=\"eladder\">true</data></edge>",
+		"<edge source=\"cog\" target=\"dog\"></edge>",
+	}
+	for _, want := range wants {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteGraphML(), want to contain %q, got=%q", want, got)
+		}
+	}
+	if strings.Count(got, "<edge ") != 2 {
+		t.Errorf("WriteGraphML(), want 2 edges, got=%q", got)
+	}
+}
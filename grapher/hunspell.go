@@ -0,0 +1,432 @@
+/*
+Reads Hunspell dictionaries, as described at:
+https://man.archlinux.org/man/hunspell.5
+Only the parts of the .aff format needed to expand stems into word forms
+are supported: FLAG, AF, NEEDAFFIX, PFX and SFX; everything else is ignored.
+*/
+package grapher
+
+import (
+		"io"
+		"fmt"
+		"bufio"
+		"strings"
+		"strconv"
+		"unicode/utf8"
+		"context"
+)
+
+// options for reading a Hunspell dictionary
+type HunspellOptions struct {
+	// when true, only the stems listed in the .dic file are added,
+	// and no affix rules are applied
+	StemsOnly bool
+	// if set, called as loading progresses, from one goroutine at a time
+	Progress func(Progress)
+	// the kinds of edge to link; zero uses DefaultMoves
+	Moves Move
+}
+
+// the ways an .aff file may encode flags, as set by its FLAG directive
+type flagMode int
+
+const (
+	flagShort flagMode = iota // one byte per flag, the default
+	flagLong                  // two bytes per flag
+	flagNum                   // comma-separated decimal numbers
+	flagUTF8                  // one utf8 rune per flag
+)
+
+// one element of an affix condition: '.', a literal, [abc] or [^abc]
+type conditionElement struct {
+	any bool
+	negate bool
+	chars string
+}
+
+// a single PFX or SFX rule line
+type affixRule struct {
+	strip string
+	add string
+	condition []conditionElement
+}
+
+// all rules sharing one flag
+type affixClass struct {
+	prefix bool
+	crossProduct bool
+	rules []affixRule
+}
+
+// the parsed contents of an .aff file
+type affixTable struct {
+	mode flagMode
+	aliases []string // AF flag vectors, referenced by 1-based index
+	needAffix string
+	classes map[string]*affixClass
+}
+
+// panics if dic == nil;
+// aff may be nil, in which case only stems are added;
+// words containing anything other than 'a' to 'z' are skipped,
+// since the graph cannot link them
+func ScanHunspellLinkCompress(dic, aff io.Reader, opts HunspellOptions) (graph WordGraph, count int, err error) {
+	return ScanHunspellLinkCompressContext(context.Background(), dic, aff, opts)
+}
+
+// as ScanHunspellLinkCompress, but if ctx is done before the graph
+// is complete, it returns ctx.Err()
+func ScanHunspellLinkCompressContext(ctx context.Context, dic, aff io.Reader, opts HunspellOptions) (graph WordGraph, count int, err error) {
+	rep := &reporter{callback: opts.Progress}
+	graph, count, err = scanHunspell(ctx, &countingReader{dic, rep}, aff, opts, rep)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err = linkCompress(ctx, graph, opts.Moves, rep); err != nil {
+		return nil, 0, err
+	}
+	return
+}
+
+// reports the bytes read through it
+type countingReader struct {
+	io.Reader
+	rep *reporter
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.rep.update(func(p *Progress) { p.BytesRead += int64(n) })
+	return n, err
+}
+
+// the number of words added between progress reports and checks
+// for cancellation, since a stem can expand into many words
+const scanReportInterval = 1024
+
+// panics if dic == nil
+func scanHunspell(ctx context.Context, dic, aff io.Reader, opts HunspellOptions, rep *reporter) (graph WordGraph, count int, err error) {
+	table := &affixTable{classes: make(map[string]*affixClass)}
+	if aff != nil {
+		if table, err = readAffixes(aff); err != nil {
+			return nil, 0, err
+		}
+	}
+	graph = make(WordGraph)
+	reported := 0
+	add := func(word string) {
+		if IsLadderWord(word) && addToGraph(word, graph) {
+			count++
+		}
+	}
+	scanner := bufio.NewScanner(dic)
+	first := true
+	for scanner.Scan() {
+		if count - reported >= scanReportInterval {
+			added := count - reported
+			rep.update(func(p *Progress) { p.WordsScanned += added })
+			reported = count
+			if err = ctx.Err(); err != nil {
+				return nil, 0, err
+			}
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if first {
+			// the first line holds an approximate entry count
+			first = false
+			if _, convErr := strconv.Atoi(line); convErr == nil {
+				continue
+			}
+		}
+		if line == "" || line[0] == '#' || line[0] == '/' {
+			continue
+		}
+		stem, flags := splitEntry(line)
+		flagList := table.parseFlags(flags)
+		if !table.has(flagList, table.needAffix) {
+			add(stem)
+		}
+		if !opts.StemsOnly {
+			table.expand(stem, flagList, add)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	added := count - reported
+	rep.update(func(p *Progress) { p.WordsScanned += added })
+	return
+}
+
+// splits a .dic line into its stem and raw flag field,
+// discarding any morphological fields that follow
+func splitEntry(line string) (stem, flags string) {
+	if i := strings.IndexAny(line, " \t"); i >= 0 {
+		line = line[:i]
+	}
+	if i := strings.IndexByte(line, '/'); i > 0 {
+		return line[:i], line[i+1:]
+	}
+	return line, ""
+}
+
+// true if word is non-empty and contains only the letters 'a' to 'z',
+// the only words the graph can link
+func IsLadderWord(word string) bool {
+	if word == "" {
+		return false
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// panics if aff == nil
+func readAffixes(aff io.Reader) (table *affixTable, err error) {
+	table = &affixTable{classes: make(map[string]*affixClass)}
+	scanner := bufio.NewScanner(aff)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "FLAG":
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("aff line %d: FLAG requires a value", lineNum)
+			}
+			switch fields[1] {
+			case "long":
+				table.mode = flagLong
+			case "num":
+				table.mode = flagNum
+			case "UTF-8":
+				table.mode = flagUTF8
+			default:
+				return nil, fmt.Errorf("aff line %d: unknown FLAG type %q", lineNum, fields[1])
+			}
+		case "AF":
+			// the first AF line is the count, the rest are flag vectors
+			if len(fields) >= 2 {
+				if _, convErr := strconv.Atoi(fields[1]); convErr == nil && table.aliases == nil {
+					table.aliases = []string{}
+					continue
+				}
+				table.aliases = append(table.aliases, fields[1])
+			}
+		case "NEEDAFFIX":
+			if len(fields) >= 2 {
+				table.needAffix = fields[1]
+			}
+		case "PFX", "SFX":
+			if err = table.addAffixLine(fields, lineNum); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// handles both the header and the rule lines of a PFX or SFX class
+func (table *affixTable) addAffixLine(fields []string, lineNum int) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("aff line %d: malformed %s entry", lineNum, fields[0])
+	}
+	flag := fields[1]
+	if _, convErr := strconv.Atoi(fields[3]); len(fields) == 4 && convErr == nil &&
+		(fields[2] == "Y" || fields[2] == "N") {
+		// header: PFX flag cross_product number
+		table.classes[flag] = &affixClass{
+			prefix: fields[0] == "PFX",
+			crossProduct: fields[2] == "Y",
+		}
+		return nil
+	}
+	class, ok := table.classes[flag]
+	if !ok {
+		return fmt.Errorf("aff line %d: %s rule for undeclared flag %q", lineNum, fields[0], flag)
+	}
+	// rule: PFX flag stripping prefix [condition [morphological_fields...]]
+	rule := affixRule{strip: fields[2], add: fields[3]}
+	if rule.strip == "0" {
+		rule.strip = ""
+	}
+	if i := strings.IndexByte(rule.add, '/'); i >= 0 {
+		// continuation classes are not supported
+		rule.add = rule.add[:i]
+	}
+	if rule.add == "0" {
+		rule.add = ""
+	}
+	condition := "."
+	if len(fields) >= 5 {
+		condition = fields[4]
+	}
+	var err error
+	if rule.condition, err = parseCondition(condition); err != nil {
+		return fmt.Errorf("aff line %d: %v", lineNum, err)
+	}
+	class.rules = append(class.rules, rule)
+	return nil
+}
+
+// parses a condition such as "[^aeiou]y" into its elements
+func parseCondition(condition string) (elements []conditionElement, err error) {
+	for i := 0; i < len(condition); {
+		switch condition[i] {
+		case '.':
+			elements = append(elements, conditionElement{any: true})
+			i++
+		case '[':
+			end := strings.IndexByte(condition[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated condition %q", condition)
+			}
+			element := conditionElement{chars: condition[i+1 : i+end]}
+			if strings.HasPrefix(element.chars, "^") {
+				element.negate = true
+				element.chars = element.chars[1:]
+			}
+			elements = append(elements, element)
+			i += end + 1
+		default:
+			_, size := utf8.DecodeRuneInString(condition[i:])
+			elements = append(elements, conditionElement{chars: condition[i : i+size]})
+			i += size
+		}
+	}
+	return
+}
+
+// true if the rune r satisfies the condition element
+func (element conditionElement) matches(r rune) bool {
+	if element.any {
+		return true
+	}
+	return strings.ContainsRune(element.chars, r) != element.negate
+}
+
+// true if the start (prefix) or end (suffix) of word satisfies condition
+func matchCondition(condition []conditionElement, word string, prefix bool) bool {
+	runes := []rune(word)
+	if len(runes) < len(condition) {
+		return false
+	}
+	offset := 0
+	if !prefix {
+		offset = len(runes) - len(condition)
+	}
+	for i, element := range condition {
+		if !element.matches(runes[offset+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// applies a rule to word, returning false if it does not apply
+func (rule affixRule) apply(word string, prefix bool) (string, bool) {
+	if !matchCondition(rule.condition, word, prefix) {
+		return "", false
+	}
+	if prefix {
+		if !strings.HasPrefix(word, rule.strip) {
+			return "", false
+		}
+		return rule.add + word[len(rule.strip):], true
+	}
+	if !strings.HasSuffix(word, rule.strip) {
+		return "", false
+	}
+	return word[:len(word)-len(rule.strip)] + rule.add, true
+}
+
+// splits a raw flag field according to the FLAG mode,
+// resolving AF aliases where the field is a plain alias number
+func (table *affixTable) parseFlags(raw string) (flags []string) {
+	if raw == "" {
+		return nil
+	}
+	if table.aliases != nil {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 1 && n <= len(table.aliases) {
+			raw = table.aliases[n-1]
+		}
+	}
+	switch table.mode {
+	case flagLong:
+		for i := 0; i+1 < len(raw); i += 2 {
+			flags = append(flags, raw[i:i+2])
+		}
+	case flagNum:
+		flags = strings.Split(raw, ",")
+	case flagUTF8:
+		for _, r := range raw {
+			flags = append(flags, string(r))
+		}
+	default:
+		for i := 0; i < len(raw); i++ {
+			flags = append(flags, raw[i:i+1])
+		}
+	}
+	return
+}
+
+// true if flag is non-empty and present in flags
+func (table *affixTable) has(flags []string, flag string) bool {
+	if flag == "" {
+		return false
+	}
+	for _, f := range flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// calls emit for every word form generated from stem by its flags,
+// including prefix + suffix combinations where both allow cross products
+func (table *affixTable) expand(stem string, flags []string, emit func(string)) {
+	var suffixed []string
+	for _, flag := range flags {
+		class, ok := table.classes[flag]
+		if !ok || class.prefix {
+			continue
+		}
+		for _, rule := range class.rules {
+			if word, ok := rule.apply(stem, false); ok {
+				emit(word)
+				if class.crossProduct {
+					suffixed = append(suffixed, word)
+				}
+			}
+		}
+	}
+	for _, flag := range flags {
+		class, ok := table.classes[flag]
+		if !ok || !class.prefix {
+			continue
+		}
+		for _, rule := range class.rules {
+			if word, ok := rule.apply(stem, true); ok {
+				emit(word)
+			}
+			if !class.crossProduct {
+				continue
+			}
+			for _, base := range suffixed {
+				if word, ok := rule.apply(base, true); ok {
+					emit(word)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"reflect"
+		"sort"
+		"context"
+)
+
+const testAff = `# a small English-like affix file
+SET UTF-8
+
+PFX R Y 1
+PFX R 0 re .
+
+SFX S Y 3
+SFX S y ies [^aeiou]y
+SFX S 0 s [aeiou]y
+SFX S 0 s [^y]
+
+SFX D N 2
+SFX D 0 d e
+SFX D 0 ed [^e]
+
+NEEDAFFIX X
+`
+
+// returns the sorted words of a scanned graph, for easy comparison
+func graphWords(graph WordGraph) (words []string) {
+	for _, subGraph := range graph {
+		for word := range subGraph {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+	return
+}
+
+func TestScanHunspell(t *testing.T) {
+	cases := []struct {
+		dic string
+		aff string
+		stemsOnly bool
+		want []string
+	}{
+		//test stems without affixes
+		{"2\ncat\ndog\n", "", false, []string{"cat", "dog"}},
+		//test suffix conditions
+		{"3\ncat/S\ntoy/S\nfly/S\n", testAff, false,
+			[]string{"cat", "cats", "flies", "fly", "toy", "toys"}},
+		//test prefix and cross product
+		{"1\nwork/RS\n", testAff, false,
+			[]string{"rework", "reworks", "work", "works"}},
+		//test suffix without cross product
+		{"2\nbake/RD\nwalk/D\n", testAff, false,
+			[]string{"bake", "baked", "rebake", "walk", "walked"}},
+		//test stems only
+		{"1\nwork/RS\n", testAff, true, []string{"work"}},
+		//test needaffix, morphology and unlinkable words
+		{"4\nplay/SX\tpo:verb\nParis\ndon't\nmoo/S st:moo\n", testAff, false,
+			[]string{"moo", "moos", "plays"}},
+	}
+	for _, c := range cases {
+		var graph WordGraph
+		var count int
+		var err error
+		if c.aff == "" {
+			graph, count, err = scanHunspell(context.Background(), strings.NewReader(c.dic), nil, HunspellOptions{StemsOnly: c.stemsOnly}, &reporter{})
+		} else {
+			graph, count, err = scanHunspell(context.Background(), strings.NewReader(c.dic), strings.NewReader(c.aff), HunspellOptions{StemsOnly: c.stemsOnly}, &reporter{})
+		}
+		if err != nil {
+			t.Errorf("scanHunspell(%q), unexpected error=%v", c.dic, err)
+			continue
+		}
+		if count != len(c.want) {
+			t.Errorf("scanHunspell(%q), wantCount=%d, gotCount=%d", c.dic, len(c.want), count)
+		}
+		if got := graphWords(graph); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("scanHunspell(%q), want=%v, got=%v", c.dic, c.want, got)
+		}
+	}
+}
+
+func TestReadAffixesFlagModes(t *testing.T) {
+	cases := []struct {
+		aff string
+		raw string
+		want []string
+	}{
+		{"FLAG long\n", "AaBb", []string{"Aa", "Bb"}},
+		{"FLAG num\n", "12,7", []string{"12", "7"}},
+		{"FLAG UTF-8\n", "ÄÖ", []string{"Ä", "Ö"}},
+		{"AF 2\nAF AB\nAF C\n", "2", []string{"C"}},
+		{"", "AB", []string{"A", "B"}},
+	}
+	for _, c := range cases {
+		table, err := readAffixes(strings.NewReader(c.aff))
+		if err != nil {
+			t.Errorf("readAffixes(%q), unexpected error=%v", c.aff, err)
+			continue
+		}
+		if got := table.parseFlags(c.raw); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseFlags(%q), want=%v, got=%v", c.raw, c.want, got)
+		}
+	}
+}
+
+func TestReadAffixesErrors(t *testing.T) {
+	cases := []string{
+		"FLAG bogus\n",
+		"SFX S 0 s .\n", // rule before header
+		"SFX S Y 1\nSFX S 0 s [ab\n",
+	}
+	for _, c := range cases {
+		if _, err := readAffixes(strings.NewReader(c)); err == nil {
+			t.Errorf("readAffixes(%q), expected an error", c)
+		}
+	}
+}
+
+func TestScanHunspellLinkCompress(t *testing.T) {
+	graph, count, err := ScanHunspellLinkCompress(strings.NewReader("1\ncat/S\n2\ncot\n"),
+		strings.NewReader(testAff), HunspellOptions{})
+	if err != nil || count != 3 {
+		t.Fatalf("ScanHunspellLinkCompress(), count=%d, err=%v", count, err)
+	}
+	neighbours := graph[3]["cat"].Neighbours
+	if len(neighbours) != 1 || neighbours[0].(*WordNode).Word != "cot" {
+		t.Errorf("ScanHunspellLinkCompress(), cat neighbours=%v", neighbours)
+	}
+}
@@ -0,0 +1,40 @@
+package grapher
+
+import (
+		"github.com/nerophon/dictdash/search"
+)
+
+// Index numbers the words of a graph densely from zero, so that searches
+// can keep their state in arrays instead of maps;
+// combined with a search.Graph it implements search.Indexer
+type Index struct {
+	count int
+}
+
+// assigns every node of graph an ID; the graph must then only gain
+// nodes through Assign, though nodes may be deleted freely
+func NewIndex(graph WordGraph) *Index {
+	ix := &Index{}
+	for _, subGraph := range graph {
+		for _, node := range subGraph {
+			ix.Assign(node)
+		}
+	}
+	return ix
+}
+
+// gives node the next unused ID
+func (ix *Index) Assign(node *WordNode) {
+	node.ID = ix.count
+	ix.count++
+}
+
+// panics if node is not a *WordNode
+func (ix *Index) Index(node search.GraphNode) int {
+	return node.(*WordNode).ID
+}
+
+// one more than the largest ID assigned
+func (ix *Index) Len() int {
+	return ix.count
+}
@@ -0,0 +1,31 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+)
+
+func TestIndex(t *testing.T) {
+	graph, _, err := ScanLinkCompress(strings.NewReader("cat cot cog dog a cold"))
+	if err != nil {
+		t.Fatalf("ScanLinkCompress(), unexpected error=%v", err)
+	}
+	ix := NewIndex(graph)
+	if ix.Len() != 6 {
+		t.Errorf("Len(), want=6, got=%d", ix.Len())
+	}
+	seen := make(map[int]bool)
+	for _, subGraph := range graph {
+		for word, node := range subGraph {
+			id := ix.Index(node)
+			if id < 0 || id >= ix.Len() || seen[id] {
+				t.Errorf("Index(%s), want a unique ID below %d, got=%d", word, ix.Len(), id)
+			}
+			seen[id] = true
+		}
+	}
+	ix.Assign(Insert(graph, "cogs"))
+	if got := ix.Index(graph[4]["cogs"]); got != 6 || ix.Len() != 7 {
+		t.Errorf("Assign(cogs), want ID 6 and Len 7, got ID %d and Len %d", got, ix.Len())
+	}
+}
@@ -0,0 +1,157 @@
+/*
+The ALT (A*, Landmarks, Triangle inequality) heuristic, as described in:
+Goldberg & Harrelson, "Computing the Shortest Path: A* Search Meets Graph Theory"
+*/
+package grapher
+
+import (
+		"github.com/nerophon/dictdash/search"
+)
+
+// the precomputed distances for one word
+type landmarkEntry struct {
+	component int
+	distances []int32 // to each landmark of the component, in order
+}
+
+// Landmarks holds the distances from a few landmark words in each component
+// to every other word in that component; for any words v and t in the same
+// component, and any landmark L, |d(L,t) - d(L,v)| <= d(v,t),
+// which often bounds a ladder far more tightly than the Hamming distance;
+// Landmarks implements search.Graph, so that it can be passed to search.AStar
+type Landmarks struct {
+	search.Nodes
+	// the moves the graph was linked with, which must be set
+	// if it has transposition or anagram edges
+	Moves Move
+	entries map[*WordNode]landmarkEntry
+	landmarks [][]*WordNode // by component
+}
+
+// the landmarks of one component, with every word's distances to them
+type landmarkComponent struct {
+	landmarks []*WordNode
+	distances map[*WordNode][]int32
+}
+
+// selects up to perComponent landmarks in every component of the graph
+// with more than two words, and records their distances to each word;
+// requires a compressed graph; the graph must not change afterwards
+func NewLandmarks(graph WordGraph, perComponent int) *Landmarks {
+	l := &Landmarks{entries: make(map[*WordNode]landmarkEntry)}
+	if perComponent <= 0 {
+		return l
+	}
+	// buckets are independent, so they are processed concurrently
+	done := make(chan []landmarkComponent, len(graph))
+	for _, subGraph := range graph {
+		go func(subGraph map[string]*WordNode) {
+			done <- landmarkSubGraph(subGraph, perComponent)
+		}(subGraph)
+	}
+	for i := 0; i < len(graph); i++ {
+		for _, c := range <-done {
+			component := len(l.landmarks)
+			l.landmarks = append(l.landmarks, c.landmarks)
+			for node, distances := range c.distances {
+				l.entries[node] = landmarkEntry{component, distances}
+			}
+		}
+	}
+	return l
+}
+
+// chooses landmarks for each component of a bucket by farthest-point
+// selection: the first is the word farthest from an arbitrary start,
+// and each later one is the word farthest from all those chosen so far
+func landmarkSubGraph(subGraph map[string]*WordNode, perComponent int) (components []landmarkComponent) {
+	seen := make(map[*WordNode]bool)
+	// visit words alphabetically, so the choice of landmarks is deterministic
+	for _, node := range sortedNodes(subGraph) {
+		if seen[node] {
+			continue
+		}
+		members := Component(node)
+		for _, n := range members {
+			seen[n] = true
+		}
+		if len(members) <= 2 {
+			continue // the Hamming distance is already exact
+		}
+		c := landmarkComponent{distances: make(map[*WordNode][]int32, len(members))}
+		nearest := bfsDistances(members[0])
+		for len(c.landmarks) < perComponent {
+			farthest := members[0]
+			for _, n := range members {
+				if nearest[n] > nearest[farthest] {
+					farthest = n
+				}
+			}
+			if nearest[farthest] == 0 && len(c.landmarks) > 0 {
+				break // every word is already a landmark
+			}
+			c.landmarks = append(c.landmarks, farthest)
+			fromLandmark := bfsDistances(farthest)
+			for _, n := range members {
+				c.distances[n] = append(c.distances[n], int32(fromLandmark[n]))
+				if len(c.landmarks) == 1 || fromLandmark[n] < nearest[n] {
+					nearest[n] = fromLandmark[n]
+				}
+			}
+		}
+		components = append(components, c)
+	}
+	return
+}
+
+// the distance from node to every word in its component;
+// requires a compressed graph
+func bfsDistances(node *WordNode) map[*WordNode]int {
+	distances := map[*WordNode]int{node: 0}
+	frontier := []*WordNode{node}
+	for len(frontier) > 0 {
+		current := frontier[0]
+		frontier = frontier[1:]
+		for _, neighbour := range current.Neighbours {
+			n := neighbour.(*WordNode)
+			if _, ok := distances[n]; !ok {
+				distances[n] = distances[current] + 1
+				frontier = append(frontier, n)
+			}
+		}
+	}
+	return distances
+}
+
+// the number of landmarks selected across all components
+func (l *Landmarks) Len() (count int) {
+	for _, componentLandmarks := range l.landmarks {
+		count += len(componentLandmarks)
+	}
+	return
+}
+
+// the larger of EstimateMoves and the landmark bound;
+// panics if node or target are not *WordNodes
+func (l *Landmarks) Estimate(node, target search.GraphNode) int {
+	from, to := node.(*WordNode), target.(*WordNode)
+	estimate := EstimateMoves(from, to, l.Moves)
+	fromEntry, ok := l.entries[from]
+	if !ok {
+		return estimate
+	}
+	toEntry, ok := l.entries[to]
+	if !ok || toEntry.component != fromEntry.component {
+		return estimate
+	}
+	for i, d := range fromEntry.distances {
+		bound := int(toEntry.distances[i] - d)
+		if bound < 0 {
+			bound = -bound
+		}
+		if bound > estimate {
+			estimate = bound
+		}
+	}
+	return estimate
+}
@@ -0,0 +1,52 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"context"
+		"github.com/nerophon/dictdash/search"
+)
+
+func TestLandmarksAdmissible(t *testing.T) {
+	// one large component, an isolated word and a pair
+	graph, _, _ := ScanLinkCompress(strings.NewReader(
+		"cold cord card ward warm word wold bold bolt boat zzzz cat cot"))
+	landmarks := NewLandmarks(graph, 2)
+	if landmarks.Len() != 2 {
+		t.Errorf("NewLandmarks(), wantLen=2, gotLen=%d", landmarks.Len())
+	}
+	tighter := 0
+	for _, subGraph := range graph {
+		for _, from := range subGraph {
+			distances := bfsDistances(from)
+			for _, to := range subGraph {
+				estimate := landmarks.Estimate(from, to)
+				hamming := from.EstimatedTargetCost(to)
+				if estimate < hamming {
+					t.Errorf("Estimate(%s, %s), want>=%d, got=%d", from.Word, to.Word, hamming, estimate)
+				}
+				if actual, ok := distances[to]; ok && estimate > actual {
+					t.Errorf("Estimate(%s, %s) is inadmissible, want<=%d, got=%d", from.Word, to.Word, actual, estimate)
+				} else if ok && estimate > hamming {
+					tighter++
+				}
+			}
+		}
+	}
+	if tighter == 0 {
+		t.Errorf("NewLandmarks(), no estimate was tighter than the Hamming distance")
+	}
+}
+
+func TestLandmarksSearch(t *testing.T) {
+	graph, _, _ := ScanLinkCompress(strings.NewReader("cold cord card ward warm word wold bold"))
+	from, to := search.GraphNode(graph[4]["cold"]), search.GraphNode(graph[4]["warm"])
+	got := search.AStar[search.GraphNode, int](context.Background(), NewLandmarks(graph, 4), from, to, search.Options{})
+	if got.Outcome != search.Found || got.Distance != 4 {
+		t.Errorf("AStar(Landmarks), want distance 4, got=%+v", got)
+	}
+	none := NewLandmarks(graph, 0)
+	if none.Len() != 0 || none.Estimate(from, to) != from.EstimatedTargetCost(to) {
+		t.Errorf("NewLandmarks(0), want the Hamming distance only")
+	}
+}
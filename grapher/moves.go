@@ -0,0 +1,217 @@
+package grapher
+
+import (
+		"sort"
+		"strings"
+		"github.com/nerophon/dictdash/search"
+)
+
+// the kinds of step a ladder may take between words of the same length;
+// combined with |, they select which edges a graph is built with
+type Move uint8
+
+const (
+	// one letter replaced by another, e.g. cold to cord
+	MoveSubstitution Move = 1 << iota
+	// two adjacent letters swapped, e.g. cold to clod
+	MoveTransposition
+	// the letters rearranged in any other way, e.g. cold to docl;
+	// where transpositions are also allowed, a single adjacent swap
+	// is a transposition rather than an anagram
+	MoveAnagram
+)
+
+// the moves used when none are chosen
+const DefaultMoves = MoveSubstitution
+
+var moveNames = []struct {
+	move Move
+	name string
+}{
+	{MoveSubstitution, "substitution"},
+	{MoveTransposition, "transposition"},
+	{MoveAnagram, "anagram"},
+}
+
+// the names of the moves in m, separated by '|'
+func (m Move) String() string {
+	var names []string
+	for _, n := range moveNames {
+		if m&n.move != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// zero is treated as DefaultMoves
+func (m Move) orDefault() Move {
+	if m == 0 {
+		return DefaultMoves
+	}
+	return m
+}
+
+// the cost of a step of kind m under search.CostWeighted: one for
+// a substitution, two for a transposition and three for an anagram,
+// so that ladders prefer the simpler kinds of step; no step costs less
+// than one, so estimates of the number of steps remain admissible
+func (m Move) Weight() int {
+	switch m {
+	case MoveTransposition:
+		return 2
+	case MoveAnagram:
+		return 3
+	}
+	return 1
+}
+
+// the kind of the edge from node to other, or zero if they are not linked;
+// requires a compressed graph
+func (node *WordNode) MoveTo(other *WordNode) Move {
+	if move, ok := node.rearranged[other]; ok {
+		return move
+	}
+	for i, v := range node.Neighbours {
+		if v == search.GraphNode(other) {
+			if node.Moves == nil {
+				return MoveSubstitution
+			}
+			return node.Moves[i]
+		}
+	}
+	return 0
+}
+
+// the kind of the edge from node to other, which must be one of its
+// Neighbours; unlike MoveTo this takes constant time
+func (node *WordNode) NeighbourMove(other *WordNode) Move {
+	if move, ok := node.rearranged[other]; ok {
+		return move
+	}
+	return MoveSubstitution
+}
+
+// links a and b by an edge of kind move; requires a compressed graph
+func addEdge(a, b *WordNode, move Move) {
+	a.addNeighbour(b, move)
+	b.addNeighbour(a, move)
+}
+
+func (node *WordNode) addNeighbour(other *WordNode, move Move) {
+	if move != MoveSubstitution && node.Moves == nil {
+		// until now every edge was a substitution
+		node.Moves = make([]Move, len(node.Neighbours), len(node.Neighbours) + 1)
+		for i := range node.Moves {
+			node.Moves[i] = MoveSubstitution
+		}
+	}
+	node.Neighbours = append(node.Neighbours, other)
+	if node.Moves != nil {
+		node.Moves = append(node.Moves, move)
+	}
+	if move != MoveSubstitution {
+		if node.rearranged == nil {
+			node.rearranged = make(map[*WordNode]Move)
+		}
+		node.rearranged[other] = move
+	}
+}
+
+// the letters of word in alphabetical order, shared by all its anagrams
+func anagramKey(word string) string {
+	letters := []byte(word)
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+	return string(letters)
+}
+
+// true if b is a with one pair of adjacent letters swapped
+func isTransposition(a, b string) bool {
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	return i + 1 < len(a) && a[i] == b[i+1] && a[i+1] == b[i] && a[i+2:] == b[i+2:]
+}
+
+// the kind of edge between two distinct anagrams, or zero if moves
+// permit none
+func anagramMove(a, b string, moves Move) Move {
+	if moves&MoveTransposition != 0 && isTransposition(a, b) {
+		return MoveTransposition
+	}
+	if moves&MoveAnagram != 0 {
+		return MoveAnagram
+	}
+	return 0
+}
+
+// adds the transposition and anagram edges of a compressed bucket
+func linkRearrangements(subGraph map[string]*WordNode, moves Move) {
+	if moves&(MoveTransposition|MoveAnagram) == 0 {
+		return
+	}
+	anagrams := make(map[string][]*WordNode)
+	for _, node := range sortedNodes(subGraph) {
+		key := anagramKey(node.Word)
+		anagrams[key] = append(anagrams[key], node)
+	}
+	for _, group := range anagrams {
+		for i, a := range group {
+			for _, b := range group[i+1:] {
+				if move := anagramMove(a.Word, b.Word, moves); move != 0 {
+					addEdge(a, b, move)
+				}
+			}
+		}
+	}
+}
+
+// MoveGraph is a search.Graph over WordNodes whose estimate remains
+// admissible when transposition or anagram edges are present
+type MoveGraph struct {
+	search.Nodes
+	Moves Move
+}
+
+func (g MoveGraph) Estimate(node, target search.GraphNode) int {
+	return EstimateMoves(node.(*WordNode), target.(*WordNode), g.Moves)
+}
+
+// supports search.CostWeighted, costing each step by its Move's Weight
+func (g MoveGraph) WeightedCost(from, to search.GraphNode) int {
+	return from.(*WordNode).NeighbourMove(to.(*WordNode)).Weight()
+}
+
+// a lower bound on the steps from one word to another of the same length,
+// in a graph with the given moves; for substitutions alone it is the
+// Hamming distance, as given by EstimatedTargetCost
+func EstimateMoves(from, to *WordNode, moves Move) int {
+	hamming := from.EstimatedTargetCost(to)
+	moves = moves.orDefault()
+	if moves == MoveSubstitution || hamming == 0 {
+		return hamming
+	}
+	// rearranging never changes which letters a word contains,
+	// so each letter in excess needs a substitution
+	var counts [256]int
+	for i := 0; i < len(from.Word); i++ {
+		counts[from.Word[i]]++
+		counts[to.Word[i]]--
+	}
+	estimate := 0
+	for _, c := range counts {
+		if c > 0 {
+			estimate += c
+		}
+	}
+	if moves&MoveAnagram != 0 {
+		// one anagram can fix any number of positions
+		return max(estimate, 1)
+	}
+	// a transposition fixes at most two positions
+	return max(estimate, (hamming+1)/2)
+}
@@ -0,0 +1,106 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"sort"
+		"context"
+)
+
+// the words of a node's neighbours, each with the kind of its edge, sorted;
+// an edge whose NeighbourMove disagrees with MoveTo shows as none
+func moveWords(node *WordNode) string {
+	var words []string
+	for _, v := range node.Neighbours {
+		neighbour := v.(*WordNode)
+		move := node.MoveTo(neighbour)
+		if node.NeighbourMove(neighbour) != move {
+			move = 0
+		}
+		words = append(words, neighbour.Word + ":" + move.String())
+	}
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+const testMoveWords = "cold clod cord lcod docl colt"
+
+func scanMoves(t *testing.T, input string, moves Move) WordGraph {
+	graph, _, err := ScanLinkCompressConcurrent(context.Background(), strings.NewReader(input), ScanOptions{Moves: moves})
+	if err != nil {
+		t.Fatalf("ScanLinkCompressConcurrent(%v), unexpected error=%v", moves, err)
+	}
+	return graph
+}
+
+func TestMoves(t *testing.T) {
+	all := MoveSubstitution | MoveTransposition | MoveAnagram
+	cases := []struct {
+		moves Move
+		word string
+		want string
+	}{
+		{0, "cold", "colt:substitution cord:substitution"},
+		{all, "cold", "clod:transposition colt:substitution cord:substitution docl:anagram lcod:anagram"},
+		{all, "clod", "cold:transposition docl:anagram lcod:transposition"},
+		{MoveTransposition, "cold", "clod:transposition"},
+		{MoveTransposition, "cord", ""},
+		{MoveAnagram, "cold", "clod:anagram docl:anagram lcod:anagram"},
+		{MoveSubstitution | MoveAnagram, "colt", "cold:substitution"},
+	}
+	for _, c := range cases {
+		graph := scanMoves(t, testMoveWords, c.moves)
+		if got := moveWords(graph[4][c.word]); got != c.want {
+			t.Errorf("Moves(%v), %s neighbours want=%q, got=%q", c.moves, c.word, c.want, got)
+		}
+	}
+	if got := (MoveSubstitution | MoveAnagram).String(); got != "substitution|anagram" {
+		t.Errorf("Move.String(), want=substitution|anagram, got=%s", got)
+	}
+	graph := scanMoves(t, testMoveWords, 0)
+	if graph[4]["cold"].Moves != nil || graph[4]["cold"].MoveTo(graph[4]["clod"]) != 0 {
+		t.Errorf("Moves(substitution), want no Moves and no edge to clod")
+	}
+}
+
+func TestEstimateMoves(t *testing.T) {
+	all := MoveSubstitution | MoveTransposition | MoveAnagram
+	for _, moves := range []Move{0, MoveSubstitution | MoveTransposition, all} {
+		graph := scanMoves(t, testMoveWords + " bold bolt boat coat cost dolt", moves)
+		for _, from := range graph[4] {
+			distances := bfsDistances(from)
+			for to, distance := range distances {
+				if estimate := EstimateMoves(from, to, moves); estimate > distance {
+					t.Errorf("EstimateMoves(%s, %s, %v), estimate=%d exceeds distance=%d", from.Word, to.Word, moves, estimate, distance)
+				}
+			}
+		}
+	}
+}
+
+func TestInsertMoves(t *testing.T) {
+	all := MoveSubstitution | MoveTransposition | MoveAnagram
+	want := scanMoves(t, testMoveWords, all)
+	graph := scanMoves(t, "cold cord", all)
+	for _, word := range []string{"lcod", "clod", "docl", "colt"} {
+		InsertMoves(graph, word, all)
+	}
+	for word, node := range want[4] {
+		if got := moveWords(graph[4][word]); got != moveWords(node) {
+			t.Errorf("InsertMoves(), %s neighbours want=%q, got=%q", word, moveWords(node), got)
+		}
+	}
+	clod := graph[4]["clod"]
+	Delete(graph, "clod")
+	for word, node := range graph[4] {
+		if node.Moves != nil && len(node.Moves) != len(node.Neighbours) {
+			t.Errorf("Delete(clod), %s has %d moves for %d neighbours", word, len(node.Moves), len(node.Neighbours))
+		}
+	}
+	if got := moveWords(graph[4]["cold"]); got != "colt:substitution cord:substitution docl:anagram lcod:anagram" {
+		t.Errorf("Delete(clod), cold neighbours got=%q", got)
+	}
+	if graph[4]["cold"].MoveTo(clod) != 0 || graph[4]["lcod"].MoveTo(clod) != 0 {
+		t.Errorf("Delete(clod), want no edge to clod")
+	}
+}
@@ -0,0 +1,93 @@
+package grapher
+
+import (
+		"github.com/nerophon/dictdash/search"
+)
+
+// changes to a compressed graph, for dictionaries that are edited after loading
+
+// adds word to a compressed graph, linking it to every word
+// one transformation away; returns nil if the word was already present;
+// panics if graph == nil or word contains anything other than 'a' to 'z'
+func Insert(graph WordGraph, word string) *WordNode {
+	return InsertMoves(graph, word, DefaultMoves)
+}
+
+// as Insert, for a graph linked with the given moves
+func InsertMoves(graph WordGraph, word string, moves Move) *WordNode {
+	if !IsLadderWord(word) {
+		panic("grapher: cannot insert " + word)
+	}
+	moves = moves.orDefault()
+	letterCount := len(word)
+	if _, ok := graph[letterCount]; !ok {
+		graph[letterCount] = make(map[string]*WordNode)
+	}
+	subGraph := graph[letterCount]
+	if _, ok := subGraph[word]; ok {
+		return nil
+	}
+	node := NewWordNode(word, 0)
+	node.Edges = nil
+	node.Neighbours = []search.GraphNode{}
+	if moves&MoveSubstitution != 0 {
+		for letter := 0; letter < letterCount; letter++ {
+			for replacement := 0; replacement < 26; replacement++ {
+				searchWord := replaceAtIndex(word, alphabet[replacement], letter)
+				if searchWord == word {
+					continue
+				}
+				if connectedNode, ok := subGraph[searchWord]; ok {
+					addEdge(node, connectedNode, MoveSubstitution)
+				}
+			}
+		}
+	}
+	if moves&(MoveTransposition|MoveAnagram) != 0 {
+		key := anagramKey(word)
+		var anagrams []*WordNode
+		for other, otherNode := range subGraph {
+			if anagramKey(other) == key {
+				anagrams = append(anagrams, otherNode)
+			}
+		}
+		sortNodes(anagrams) // so that the order of neighbours is repeatable
+		for _, other := range anagrams {
+			if move := anagramMove(word, other.Word, moves); move != 0 {
+				addEdge(node, other, move)
+			}
+		}
+	}
+	subGraph[word] = node
+	return node
+}
+
+// removes word from a compressed graph, unlinking it from its neighbours;
+// returns false if the word was not present; panics if graph == nil
+func Delete(graph WordGraph, word string) bool {
+	node, ok := graph[len(word)][word]
+	if !ok {
+		return false
+	}
+	for _, neighbour := range node.Neighbours {
+		n := neighbour.(*WordNode)
+		for i, v := range n.Neighbours {
+			if v == search.GraphNode(node) {
+				n.Neighbours = append(n.Neighbours[:i], n.Neighbours[i+1:]...)
+				if n.Moves != nil {
+					n.Moves = append(n.Moves[:i], n.Moves[i+1:]...)
+				}
+				delete(n.rearranged, node)
+				break
+			}
+		}
+	}
+	node.Neighbours = nil
+	node.Moves = nil
+	node.rearranged = nil
+	delete(graph[len(word)], word)
+	if len(graph[len(word)]) == 0 {
+		delete(graph, len(word))
+	}
+	return true
+}
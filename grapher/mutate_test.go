@@ -0,0 +1,83 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+)
+
+// the words of a node's neighbours, sorted
+func neighbourWords(node *WordNode) string {
+	nodes := make([]*WordNode, len(node.Neighbours))
+	for i, v := range node.Neighbours {
+		nodes[i] = v.(*WordNode)
+	}
+	sortNodes(nodes)
+	return nodeWords(nodes)
+}
+
+func TestInsert(t *testing.T) {
+	graph, _, _ := ScanLinkCompress(strings.NewReader("cat hat cog"))
+	cot := Insert(graph, "cot")
+	if cot == nil || graph[3]["cot"] != cot {
+		t.Fatalf("Insert(cot), node not added")
+	}
+	cases := []struct {
+		word string
+		want string
+	}{
+		{"cot", "cat cog"},
+		{"cat", "cot hat"},
+		{"cog", "cot"},
+		{"hat", "cat"},
+	}
+	for _, c := range cases {
+		if got := neighbourWords(graph[3][c.word]); got != c.want {
+			t.Errorf("Insert(cot), %s neighbours want=%q, got=%q", c.word, c.want, got)
+		}
+	}
+	if Insert(graph, "cot") != nil {
+		t.Errorf("Insert(cot) twice, want nil")
+	}
+	if Insert(graph, "lengthy") == nil || len(graph[7]) != 1 {
+		t.Errorf("Insert(lengthy), want a new bucket")
+	}
+	// an inserted graph matches one scanned from scratch
+	scanned, _, _ := ScanLinkCompress(strings.NewReader("cat hat cog cot lengthy"))
+	for _, node := range scanned.Bucket(3) {
+		if got, want := neighbourWords(graph[3][node.Word]), neighbourWords(node); got != want {
+			t.Errorf("Insert(), %s neighbours want=%q, got=%q", node.Word, want, got)
+		}
+	}
+}
+
+func TestInsertInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Insert(Cat), expected a panic")
+		}
+	}()
+	Insert(WordGraph{}, "Cat")
+}
+
+func TestDelete(t *testing.T) {
+	graph, _, _ := ScanLinkCompress(strings.NewReader("cat cot cog hat lengthy"))
+	if !Delete(graph, "cot") {
+		t.Fatalf("Delete(cot), want true")
+	}
+	if Delete(graph, "cot") || Delete(graph, "zzz") {
+		t.Errorf("Delete(), want false for absent words")
+	}
+	if _, ok := graph[3]["cot"]; ok {
+		t.Errorf("Delete(cot), word still present")
+	}
+	if got := neighbourWords(graph[3]["cat"]); got != "hat" {
+		t.Errorf("Delete(cot), cat neighbours want=%q, got=%q", "hat", got)
+	}
+	if got := neighbourWords(graph[3]["cog"]); got != "" {
+		t.Errorf("Delete(cot), cog neighbours want none, got=%q", got)
+	}
+	Delete(graph, "lengthy")
+	if _, ok := graph[7]; ok {
+		t.Errorf("Delete(lengthy), want the empty bucket removed")
+	}
+}
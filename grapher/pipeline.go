@@ -0,0 +1,225 @@
+package grapher
+
+import (
+		"io"
+		"bytes"
+		"strings"
+		"sync"
+		"runtime"
+		"context"
+)
+
+// scanning as a pipeline, for word lists too large to scan on one goroutine:
+// the input is read in chunks, which are split into words in parallel;
+// the words are passed to shards, each owning the buckets of some lengths,
+// which deduplicate them without locking and link and compress their buckets
+// as soon as the input is exhausted
+
+// the number of bytes read at a time, unless set in ScanOptions
+const defaultChunkSize = 1 << 20
+
+// options for scanning a whitespace-delimited word list
+type ScanOptions struct {
+	// the number of goroutines splitting and deduplicating words;
+	// zero uses runtime.GOMAXPROCS
+	Workers int
+	// the number of bytes read at a time; zero uses 1MiB
+	ChunkSize int
+	// if set, called as loading progresses, from one goroutine at a time;
+	// it should return quickly, since the pipeline waits for it
+	Progress func(Progress)
+	// the kinds of edge to link; zero uses DefaultMoves
+	Moves Move
+}
+
+// how far loading has got
+type Progress struct {
+	BytesRead int64
+	WordsScanned int // distinct words found so far
+	Buckets int // the number of word lengths to link, known once scanning ends
+	BucketsLinked int
+	BucketsCompressed int
+}
+
+// serialises progress updates from the stages of the pipeline
+type reporter struct {
+	mu sync.Mutex
+	progress Progress
+	callback func(Progress)
+}
+
+// applies change to the progress, then reports it
+func (r *reporter) update(change func(*Progress)) {
+	if r.callback == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	change(&r.progress)
+	r.callback(r.progress)
+}
+
+// the buckets whose lengths are congruent to the shard's index,
+// modulo the number of shards
+type shard struct {
+	in chan []string
+	graph WordGraph
+	count int
+	err error
+}
+
+// as ScanLinkCompress, but scanning concurrently within the limits of opts;
+// if ctx is done before the graph is complete, it returns ctx.Err();
+// panics if input == nil
+func ScanLinkCompressConcurrent(ctx context.Context, input io.Reader, opts ScanOptions) (graph WordGraph, count int, err error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	rep := &reporter{callback: opts.Progress}
+
+	var shardsDone sync.WaitGroup
+	shards := make([]*shard, workers)
+	for i := range shards {
+		shards[i] = &shard{in: make(chan []string, workers), graph: make(WordGraph)}
+		shardsDone.Add(1)
+		go shards[i].run(ctx, opts.Moves, &shardsDone, rep)
+	}
+	chunks := make(chan []byte, workers)
+	var tokenisersDone sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		tokenisersDone.Add(1)
+		go tokenise(chunks, shards, &tokenisersDone)
+	}
+	err = readChunks(ctx, input, chunkSize, chunks, rep)
+	tokenisersDone.Wait()
+	for _, s := range shards {
+		close(s.in)
+	}
+	shardsDone.Wait()
+	for _, s := range shards {
+		if err == nil {
+			err = s.err
+		}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	graph = make(WordGraph)
+	for _, s := range shards {
+		for letterCount, subGraph := range s.graph {
+			graph[letterCount] = subGraph
+		}
+		count += s.count
+	}
+	return
+}
+
+// sends the input to chunks in pieces of about chunkSize bytes,
+// each ending between words, then closes chunks
+func readChunks(ctx context.Context, input io.Reader, chunkSize int, chunks chan<- []byte, rep *reporter) error {
+	defer close(chunks)
+	var carry []byte // the start of a word cut off by the end of the last chunk
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		buffer := make([]byte, len(carry) + chunkSize)
+		copy(buffer, carry)
+		n, err := io.ReadFull(input, buffer[len(carry):])
+		buffer = buffer[:len(carry) + n]
+		rep.update(func(p *Progress) { p.BytesRead += int64(n) })
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			if len(buffer) > 0 {
+				chunks <- buffer
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// chunks are only read after being sent, so carry may share memory
+		cut := bytes.LastIndexAny(buffer, " \t\n\v\f\r") + 1
+		carry = buffer[cut:]
+		if cut > 0 {
+			chunks <- buffer[:cut]
+		}
+	}
+}
+
+// splits chunks into words, and passes each chunk's words to the shards
+// owning their lengths
+func tokenise(chunks <-chan []byte, shards []*shard, done *sync.WaitGroup) {
+	defer done.Done()
+	batches := make([][]string, len(shards))
+	for chunk := range chunks {
+		for _, word := range strings.Fields(string(chunk)) {
+			i := len(word) % len(shards)
+			batches[i] = append(batches[i], word)
+		}
+		for i, batch := range batches {
+			if len(batch) > 0 {
+				shards[i].in <- batch
+				batches[i] = nil
+			}
+		}
+	}
+}
+
+// adds words to the shard's buckets until its input is closed,
+// then links and compresses them
+func (s *shard) run(ctx context.Context, moves Move, done *sync.WaitGroup, rep *reporter) {
+	defer done.Done()
+	for batch := range s.in {
+		if ctx.Err() != nil {
+			continue // drained, so that the tokenisers are not blocked
+		}
+		added := 0
+		for _, word := range batch {
+			if addToGraph(word, s.graph) {
+				added++
+			}
+		}
+		s.count += added
+		rep.update(func(p *Progress) { p.WordsScanned += added })
+	}
+	s.err = linkCompress(ctx, s.graph, moves, rep)
+}
+
+// links and then compresses each bucket of graph on its own goroutine,
+// reporting as each finishes; if ctx is done first, returns ctx.Err()
+// and leaves the graph partly linked
+func linkCompress(ctx context.Context, graph WordGraph, moves Move, rep *reporter) error {
+	moves = moves.orDefault()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	rep.update(func(p *Progress) { p.Buckets += len(graph) })
+	errs := make(chan error, len(graph))
+	for letterCount, subGraph := range graph {
+		go func(letterCount int, subGraph map[string]*WordNode) {
+			if moves&MoveSubstitution != 0 {
+				if err := linkBucket(ctx, letterCount, subGraph); err != nil {
+					errs <- err
+					return
+				}
+			}
+			rep.update(func(p *Progress) { p.BucketsLinked++ })
+			compressBucket(letterCount, subGraph)
+			linkRearrangements(subGraph, moves)
+			rep.update(func(p *Progress) { p.BucketsCompressed++ })
+			errs <- nil
+		}(letterCount, subGraph)
+	}
+	var err error
+	for range graph {
+		if bucketErr := <-errs; bucketErr != nil {
+			err = bucketErr
+		}
+	}
+	return err
+}
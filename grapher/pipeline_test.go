@@ -0,0 +1,120 @@
+package grapher
+
+import (
+		"testing"
+		"strings"
+		"reflect"
+		"errors"
+		"context"
+)
+
+// scans, links and compresses on a single goroutine, for comparison
+func scanLinkCompressSequential(input string) (WordGraph, int) {
+	graph := make(WordGraph)
+	count := 0
+	for _, word := range strings.Fields(input) {
+		if addToGraph(word, graph) {
+			count++
+		}
+	}
+	for letterCount, subGraph := range graph {
+		linkBucket(context.Background(), letterCount, subGraph)
+		compressBucket(letterCount, subGraph)
+	}
+	return graph, count
+}
+
+func TestScanLinkCompressConcurrent(t *testing.T) {
+	inputs := []string{
+		"",
+		"   \n\t ",
+		"how are you",
+		"thou art more lovely and more temperate\nthou art\n",
+		"cold cord card ward warm word wold cold\tbold\r\nbolt boat coat cost",
+		"supercalifragilisticexpialidocious a",
+	}
+	options := []ScanOptions{
+		{},
+		{Workers: 1, ChunkSize: 1},
+		{Workers: 3, ChunkSize: 4},
+		{Workers: 8, ChunkSize: 7},
+	}
+	for _, input := range inputs {
+		want, wantCount := scanLinkCompressSequential(input)
+		for _, opts := range options {
+			var last Progress
+			opts.Progress = func(p Progress) {
+				last = p
+			}
+			got, count, err := ScanLinkCompressConcurrent(context.Background(), strings.NewReader(input), opts)
+			if err != nil || count != wantCount || !reflect.DeepEqual(got, want) {
+				t.Errorf("ScanLinkCompressConcurrent(%q, %+v), want=%v (%d), got=%v (%d), err=%v",
+					input, opts, want, wantCount, got, count, err)
+			}
+			if last.BytesRead != int64(len(input)) || last.WordsScanned != wantCount ||
+				last.BucketsLinked != len(want) || last.BucketsCompressed != len(want) || last.Buckets != len(want) {
+				t.Errorf("ScanLinkCompressConcurrent(%q, %+v), want final progress of %d bytes, %d words and %d buckets, got=%+v",
+					input, opts, len(input), wantCount, len(want), last)
+			}
+		}
+	}
+}
+
+// fails after returning its contents once
+type failingReader struct {
+	contents string
+}
+
+var errRead = errors.New("read failed")
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.contents == "" {
+		return 0, errRead
+	}
+	n := copy(p, r.contents)
+	r.contents = r.contents[n:]
+	return n, nil
+}
+
+func TestScanLinkCompressConcurrentError(t *testing.T) {
+	graph, count, err := ScanLinkCompressConcurrent(context.Background(), &failingReader{"cold cord card "}, ScanOptions{ChunkSize: 4})
+	if !errors.Is(err, errRead) || graph != nil || count != 0 {
+		t.Errorf("ScanLinkCompressConcurrent(failing), want errRead and no graph, got=%v (%d), err=%v", graph, count, err)
+	}
+}
+
+func TestScanCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	graph, _, err := ScanLinkCompressConcurrent(ctx, strings.NewReader("cold cord card"), ScanOptions{})
+	if !errors.Is(err, context.Canceled) || graph != nil {
+		t.Errorf("ScanLinkCompressConcurrent(cancelled), want context.Canceled, got=%v, err=%v", graph, err)
+	}
+	graph, _, err = ScanHunspellLinkCompressContext(ctx, strings.NewReader("cold\ncord\n"), nil, HunspellOptions{})
+	if !errors.Is(err, context.Canceled) || graph != nil {
+		t.Errorf("ScanHunspellLinkCompressContext(cancelled), want context.Canceled, got=%v, err=%v", graph, err)
+	}
+	// cancelling part way through, once some progress has been made
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	opts := ScanOptions{Workers: 2, ChunkSize: 8, Progress: func(p Progress) {
+		if p.WordsScanned > 0 {
+			cancel()
+		}
+	}}
+	graph, _, err = ScanLinkCompressConcurrent(ctx, strings.NewReader(strings.Repeat("cold cord card ward ", 100)), opts)
+	if !errors.Is(err, context.Canceled) || graph != nil {
+		t.Errorf("ScanLinkCompressConcurrent(cancelled later), want context.Canceled, got=%v, err=%v", graph, err)
+	}
+}
+
+func TestHunspellProgress(t *testing.T) {
+	var last Progress
+	dic := "2\ncat/S\ncot\n"
+	_, count, err := ScanHunspellLinkCompressContext(context.Background(), strings.NewReader(dic),
+		strings.NewReader(testAff), HunspellOptions{Progress: func(p Progress) { last = p }})
+	want := Progress{BytesRead: int64(len(dic)), WordsScanned: count, Buckets: 2, BucketsLinked: 2, BucketsCompressed: 2}
+	if err != nil || last != want {
+		t.Errorf("ScanHunspellLinkCompressContext(), want final progress=%+v, got=%+v, err=%v", want, last, err)
+	}
+}
@@ -4,7 +4,7 @@ package grapher
 
 import (
 		"io"
-		"bufio"
+		"context"
 		"container/list"
 		"github.com/nerophon/dictdash/search"
 )
@@ -24,29 +24,10 @@ const initialEdgeCount uint = 26
 type WordGraph map[int]map[string]*WordNode
 
 
-// panics if input == nil
+// panics if input == nil;
+// the same as ScanLinkCompressConcurrent with the default ScanOptions
 func ScanLinkCompress(input io.Reader) (graph WordGraph, count int, err error) {
-	graph, count, err = scan(input)
-	link(graph)
-	compress(graph)
-	return
-}
-
-// panics if input == nil
-func scan(input io.Reader) (graph WordGraph, count int, err error) {
-	graph = make(WordGraph)
-	scanner := bufio.NewScanner(input)
-	scanner.Split(bufio.ScanWords)
-	for scanner.Scan() {
-		if addToGraph(scanner.Text(), graph) {
-			count++
-		}
-	}
-	if err = scanner.Err(); err != nil {
-		graph = nil
-		return
-	}
-	return
+	return ScanLinkCompressConcurrent(context.Background(), input, ScanOptions{})
 }
 
 // panics if graph == nil
@@ -62,40 +43,40 @@ func addToGraph(word string, graph WordGraph) (added bool) {
 	return true;
 }
 
-// panics if graph == nil
-func link(graph WordGraph) {
-	// opportunity for concurrency here
-	done := make(chan bool, len(graph))
-	for letterCount, subGraph := range graph {
-		go linkSubGraph(letterCount, subGraph, done)
-	}
-	for i := 0; i < len(graph); i++ {
-		<-done
-	}
-	return
-}
+// the number of words linked between checks for cancellation
+const linkCheckInterval = 1024
 
-func linkSubGraph(letterCount int, subGraph map[string]*WordNode, done chan bool) {
+// links one bucket, returning ctx.Err() if ctx is done before it finishes
+func linkBucket(ctx context.Context, letterCount int, subGraph map[string]*WordNode) error {
+	// candidates are built in a reused buffer; looking up string(buffer)
+	// in a map does not allocate, unlike replaceAtIndex
+	buffer := make([]byte, letterCount)
+	linked := 0
 	for word, node := range subGraph {
+		if linked++; linked % linkCheckInterval == 0 && ctx.Err() != nil {
+			return ctx.Err()
+		}
+		copy(buffer, word)
 		for letter := 0; letter < letterCount; letter++ {
+			original := word[letter]
 			for replacement := 0; replacement < 26; replacement++ {
-				searchWord := replaceAtIndex(word, alphabet[replacement], letter)
-				if searchWord == word {
+				if alphabet[replacement] == original {
 					continue // self should remain nil
 				}
 				if node.Edges[letter][replacement] != nil {
 					continue // mirror, no need to lookup
 				}
-				if connectedNode, ok := subGraph[searchWord]; ok {
-					original := word[letter]
+				buffer[letter] = alphabet[replacement]
+				if connectedNode, ok := subGraph[string(buffer)]; ok {
 					originalZeroIndexed := original - decodeOffset
 					node.Edges[letter][replacement] = connectedNode
 					connectedNode.Edges[letter][originalZeroIndexed] = node
 				}
 			}
+			buffer[letter] = original
 		}
 	}
-	done <- true
+	return nil
 }
 
 // panics if i > len(in)
@@ -105,20 +86,8 @@ func replaceAtIndex(in string, b byte, i int) string {
     return string(out)
 }
 
-// panics if graph == nil
-func compress(graph WordGraph) {
-	// opportunity for concurrency here
-	done := make(chan bool, len(graph))
-	for letterCount, subGraph := range graph {
-		go compressSubGraph(letterCount, subGraph, done)
-	}
-	for i := 0; i < len(graph); i++ {
-		<-done
-	}
-	return
-}
-
-func compressSubGraph (letterCount int, subGraph map[string]*WordNode, done chan bool) {
+// replaces each node's Edges with its Neighbours
+func compressBucket(letterCount int, subGraph map[string]*WordNode) {
 	for _, node := range subGraph {
 		list := list.New()
 		for letter := 0; letter < letterCount; letter++ {
@@ -131,7 +100,6 @@ func compressSubGraph (letterCount int, subGraph map[string]*WordNode, done chan
 		node.Neighbours = listToNodeSlice(list)
 		node.Edges = nil
 	}
-	done <- true
 }
 
 // panics if l is null or contains non-*WordNode values
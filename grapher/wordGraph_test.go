@@ -4,6 +4,7 @@ import (
 		"testing"
 		"strings"
 		"reflect"
+		"context"
 		"container/list"
 		"github.com/nerophon/dictdash/search"
 )
@@ -67,11 +68,16 @@ func TestAddToGraph(t *testing.T) {
 	for _, c := range cases {
 		added := addToGraph(c.inWord, c.inGraph)
 		if added != c.expAdded {
-			t.Errorf("addToDictionary(%s), expAdded=%b, added=%b", c.inWord, c.expAdded, added)
+			t.Errorf("addToDictionary(%s), expAdded=%t, added=%t", c.inWord, c.expAdded, added)
 		}
 	}	
 }
 
+// a compressed node without neighbours
+func isolatedNode(word string) *WordNode {
+	return &WordNode{Word: word, Neighbours: []search.GraphNode{}}
+}
+
 func TestScan(t *testing.T) {
 	//t.SkipNow()
 	cases := []struct {
@@ -86,51 +92,51 @@ func TestScan(t *testing.T) {
 		//test basic input
 		{"how are you", nil, 3, WordGraph{
 			3: map[string]*WordNode{
-				"how": NewWordNode("how", initialEdgeCount),
-				"are": NewWordNode("are", initialEdgeCount),
-				"you": NewWordNode("you", initialEdgeCount),
+				"how": isolatedNode("how"),
+				"are": isolatedNode("are"),
+				"you": isolatedNode("you"),
 				},
 			},
 		},
 		//test capitalized input
 		{"how are YOU", nil, 3, WordGraph{
 			3: map[string]*WordNode{
-				"how": NewWordNode("how", initialEdgeCount),
-				"are": NewWordNode("are", initialEdgeCount),
-				"YOU": NewWordNode("YOU", initialEdgeCount),
+				"how": isolatedNode("how"),
+				"are": isolatedNode("are"),
+				"YOU": isolatedNode("YOU"),
 				},
 			},
 		},
 		//test multiple word lengths and repeated words
 		{"thou art more lovely and more temperate", nil, 6, WordGraph{
 			3: map[string]*WordNode{
-				"art": NewWordNode("art", initialEdgeCount),
-				"and": NewWordNode("and", initialEdgeCount),
+				"art": isolatedNode("art"),
+				"and": isolatedNode("and"),
 				},
 			4: map[string]*WordNode{
-				"thou": NewWordNode("thou", initialEdgeCount),
-				"more": NewWordNode("more", initialEdgeCount),
+				"thou": isolatedNode("thou"),
+				"more": isolatedNode("more"),
 				},
 			6: map[string]*WordNode{
-				"lovely": NewWordNode("lovely", initialEdgeCount),
+				"lovely": isolatedNode("lovely"),
 				},
 			9: map[string]*WordNode{
-				"temperate": NewWordNode("temperate", initialEdgeCount),
+				"temperate": isolatedNode("temperate"),
 				},
 			},
 		},
 	}
 	for _, c := range cases {
 		input := strings.NewReader(c.in)
-		graph, count, err := scan(input)
+		graph, count, err := ScanLinkCompress(input)
 		if err != c.expErr {
-			t.Errorf("Scan(%v), expected=%v, actual=%v", c.in, c.expErr, err)
+			t.Errorf("ScanLinkCompress(%v), expected=%v, actual=%v", c.in, c.expErr, err)
 		}
 		if count != c.expCount {
-			t.Errorf("Scan(%d), expected=%d, actual=%d", c.in, c.expCount, count)
+			t.Errorf("ScanLinkCompress(%v), expected=%d, actual=%d", c.in, c.expCount, count)
 		}
 		if !reflect.DeepEqual(graph, c.expGraph) {
-			t.Errorf("Scan(%v), expected=%v, actual=%v", c.in, c.expGraph, graph)
+			t.Errorf("ScanLinkCompress(%v), expected=%v, actual=%v", c.in, c.expGraph, graph)
 		}
 	}
 }
@@ -225,7 +231,9 @@ func TestLink(t *testing.T) {
 	cases[2].want[4]["cart"].Edges[0][15] = cases[2].want[4]["part"]
 
 	for _, c := range cases {
-		link(c.in)
+		for letterCount, subGraph := range c.in {
+			linkBucket(context.Background(), letterCount, subGraph)
+		}
 		if !reflect.DeepEqual(c.in, c.want) {
 			t.Errorf("graph(), want=%v, got=%v", c.want, c.in)
 		}
@@ -303,7 +311,7 @@ func TestCompress(t *testing.T) {
 	// can't use a literal to assign a pointer conveniently,
 	// so let's just manually add the associations.
 
-	// here we add the initial edges, as if link() had been run
+	// here we add the initial edges, as if linkBucket() had been run
 	cases[1].in[3]["hit"].Edges[1][0] = cases[1].in[3]["hat"]
 	cases[1].in[3]["hit"].Edges[1][14] = cases[1].in[3]["hot"]
 	cases[1].in[3]["hat"].Edges[1][8] = cases[1].in[3]["hit"]
@@ -340,7 +348,9 @@ func TestCompress(t *testing.T) {
 	cases[1].want[4]["cart"].Neighbours[0] = search.GraphNode(cases[1].want[4]["part"])
 
 	for _, c := range cases {
-		compress(c.in)
+		for letterCount, subGraph := range c.in {
+			compressBucket(letterCount, subGraph)
+		}
 		if !reflect.DeepEqual(c.in, c.want) {
 			t.Errorf("compress(), want=%v, got=%v", c.want, c.in)
 		}
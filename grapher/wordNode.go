@@ -12,11 +12,19 @@ import (
 // during a scan, the replacementIndex is fully allocated to a size of 26,
 // and represents the index of the replacement letter in the alphabet;
 // the Neighbours structure is a compressed version of edges,
-// containing all edges in a flat list without gaps
+// containing all edges in a flat list without gaps;
+// Moves[i] is the kind of the edge to Neighbours[i],
+// and is nil when every edge is a substitution;
+// rearranged holds the same kinds for the other edges, keyed by neighbour,
+// so that a search can cost a step without scanning Neighbours;
+// the ID is only meaningful once assigned by NewIndex
 type WordNode struct {
 	Word string
 	Edges [][]*WordNode
 	Neighbours []search.GraphNode
+	Moves []Move
+	ID int
+	rearranged map[*WordNode]Move
 }
 
 func NewWordNode(word string, edgeCount uint) (node *WordNode) {
@@ -10,9 +10,9 @@ func TestNodeToString(t *testing.T) {
 		in *WordNode
 		want string
 	}{
-		{&WordNode{"", nil, nil,}, "\nWord: \nEdges: nil\nNeighbours: nil\n"},
-		{&WordNode{"a", [][]*WordNode{[]*WordNode{},}, nil,}, "\nWord: a\nEdges: \n\t0:[]\nNeighbours: nil\n"},
-		{&WordNode{"hi", [][]*WordNode{[]*WordNode{nil,nil,nil,}, []*WordNode{nil,nil,nil,},}, nil, }, "\nWord: hi\nEdges: \n\t0:[-,-,-,]\n\t1:[-,-,-,]\nNeighbours: nil\n"},
+		{&WordNode{Word: ""}, "\nWord: \nEdges: nil\nNeighbours: nil\n"},
+		{&WordNode{Word: "a", Edges: [][]*WordNode{[]*WordNode{},}}, "\nWord: a\nEdges: \n\t0:[]\nNeighbours: nil\n"},
+		{&WordNode{Word: "hi", Edges: [][]*WordNode{[]*WordNode{nil,nil,nil,}, []*WordNode{nil,nil,nil,},}}, "\nWord: hi\nEdges: \n\t0:[-,-,-,]\n\t1:[-,-,-,]\nNeighbours: nil\n"},
 	}
 	for _, c := range cases {
 		got := c.in.String()
@@ -29,9 +29,9 @@ func TestNewWordNode(t *testing.T) {
 		inEdgeCount uint
 		want *WordNode
 	}{
-		{"", 5, &WordNode{"", [][]*WordNode{}, nil,},},
-		{"hi", 3, &WordNode{"hi", [][]*WordNode{[]*WordNode{nil,nil,nil,}, []*WordNode{nil,nil,nil,},}, nil,},},
-		{"a", 0, &WordNode{"a", [][]*WordNode{[]*WordNode{},}, nil,},},
+		{"", 5, &WordNode{Word: "", Edges: [][]*WordNode{}},},
+		{"hi", 3, &WordNode{Word: "hi", Edges: [][]*WordNode{[]*WordNode{nil,nil,nil,}, []*WordNode{nil,nil,nil,},}},},
+		{"a", 0, &WordNode{Word: "a", Edges: [][]*WordNode{[]*WordNode{},}},},
 	}
 	for _, c := range cases {
 		got := NewWordNode(c.inWord, c.inEdgeCount)
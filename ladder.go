@@ -0,0 +1,137 @@
+package main
+
+import (
+		"io"
+		"os"
+		"fmt"
+		"strings"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+// how ladders are printed
+type ladderStyle int
+
+const (
+	styleAuto ladderStyle = iota // styleColour on a terminal, otherwise stylePlain
+	stylePlain // "k: word", for output read by other programs
+	styleBrackets // aligned columns, with changed letters in brackets
+	styleColour // aligned columns, with changed letters highlighted
+)
+
+// the output command's names for each style
+var ladderStyles = map[string]ladderStyle{
+	"auto": styleAuto,
+	"plain": stylePlain,
+	"brackets": styleBrackets,
+	"colour": styleColour,
+}
+
+// as set by the output command
+var ladderOutput = styleAuto
+
+// ANSI escape sequences for highlighting changed letters
+const (
+	highlightOn = "\x1b[1;33m"
+	highlightOff = "\x1b[0m"
+)
+
+// the style to use for printing to f
+func (style ladderStyle) resolve(f *os.File) ladderStyle {
+	if style != styleAuto {
+		return style
+	}
+	if !isTerminal(f) {
+		return stylePlain
+	}
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return styleBrackets
+	}
+	return styleColour
+}
+
+// true if f is a terminal rather than a file or pipe
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writes each word of the ladder on its own line;
+// other than in stylePlain, each step is shown with its kind of move
+// and the letters it changed
+func printLadder(w io.Writer, ladder dictionary.Ladder, style ladderStyle) {
+	path := ladder.Words
+	if style == stylePlain || style == styleAuto {
+		for k, v := range path {
+			if k > 0 && ladder.Moves[k-1] != dictionary.MoveSubstitution {
+				fmt.Fprintf(w, "%d: %s (%v)\n", k, v, ladder.Moves[k-1])
+				continue
+			}
+			fmt.Fprintf(w, "%d: %s\n", k, v)
+		}
+		return
+	}
+	marked := make([]string, len(path))
+	widths := make([]int, len(path)) // of the marked words, as displayed
+	wordWidth := len("word")
+	for k, v := range path {
+		marked[k], widths[k] = v, len(v)
+		if k > 0 {
+			marked[k], widths[k] = markChanges(path[k-1], v, style)
+		}
+		wordWidth = max(wordWidth, widths[k])
+	}
+	stepWidth := max(len("step"), len(fmt.Sprint(len(path)-1)))
+	moveWidth := len("transposition")
+	fmt.Fprintf(w, "%*s  %-*s  %-*s  %s\n", stepWidth, "step", wordWidth, "word", moveWidth, "move", "change")
+	for k := range path {
+		padding := strings.Repeat(" ", wordWidth - widths[k])
+		if k == 0 {
+			fmt.Fprintf(w, "%*d  %s\n", stepWidth, k, marked[k])
+			continue
+		}
+		move := ladder.Moves[k-1]
+		fmt.Fprintf(w, "%*d  %s%s  %-*v  %s\n", stepWidth, k, marked[k], padding,
+			moveWidth, move, describeChange(path[k-1], path[k], move))
+	}
+}
+
+// to, with each run of letters that differ from from marked according to
+// style, and the number of characters the result occupies on screen
+func markChanges(from, to string, style ladderStyle) (string, int) {
+	open, close := "[", "]"
+	if style == styleColour {
+		open, close = highlightOn, highlightOff
+	}
+	var b strings.Builder
+	width := len(to)
+	for i := 0; i < len(to); i++ {
+		if from[i] == to[i] {
+			b.WriteByte(to[i])
+			continue
+		}
+		j := i
+		for j < len(to) && from[j] != to[j] {
+			j++
+		}
+		b.WriteString(open + to[i:j] + close)
+		if style != styleColour {
+			width += len(open) + len(close)
+		}
+		i = j - 1
+	}
+	return b.String(), width
+}
+
+// the letters replaced or swapped by a step, such as "b → j"
+func describeChange(from, to string, move dictionary.Move) string {
+	if move == dictionary.MoveAnagram {
+		return from + " → " + to
+	}
+	var before, after []byte
+	for i := 0; i < len(to); i++ {
+		if from[i] != to[i] {
+			before, after = append(before, from[i]), append(after, to[i])
+		}
+	}
+	return string(before) + " → " + string(after)
+}
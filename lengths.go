@@ -0,0 +1,91 @@
+package main
+
+import (
+		"io"
+		"fmt"
+		"strconv"
+		"strings"
+		"text/tabwriter"
+		"github.com/nerophon/dictdash/grapher"
+)
+
+// the width of the longest bar of a histogram
+const histogramWidth = 50
+
+// shows how many steps the shortest ladders take: from the word target to
+// every other word of its length, or between every pair of words if target
+// is a length; args are the options samples=k (or all), seed=s and csv=file
+func lengthsCmd(w io.Writer, target string, args []string) error {
+	if dict == nil {
+		return errNoDictionary
+	}
+	s := newSampling()
+	for _, arg := range args {
+		isOption, err := s.parse(arg)
+		if err != nil {
+			return err
+		}
+		if !isOption {
+			return errUsage
+		}
+	}
+	graph := dict.Graph()
+	var d grapher.Distribution
+	var summary, counted string
+	if letterCount, err := strconv.Atoi(target); err == nil {
+		words := len(graph[letterCount])
+		if letterCount <= 0 || words == 0 {
+			return fmt.Errorf("there are no words of length %d", letterCount)
+		}
+		d = graph.LadderLengths(letterCount, s.among(words))
+		summary = fmt.Sprintf("Of the %.0f pairs of the %d words of length %d, %.0f are joined by ladders, %.2f steps long on average%s.\n",
+			d.Pairs() + d.Unreachable, words, letterCount, d.Pairs(), d.Mean(), s.describe(words))
+		counted = "pairs"
+	} else {
+		node, err := exploreNode(target)
+		if err != nil {
+			return err
+		}
+		d = graph.LadderLengthsFrom(node)
+		summary = fmt.Sprintf("Ladders from %s reach %.0f other %s, %.2f steps long on average; %.0f %s of length %d cannot be reached.\n",
+			target, d.Pairs(), plural(int(d.Pairs()), "word", "words"), d.Mean(),
+			d.Unreachable, plural(int(d.Unreachable), "word", "words"), len(target))
+		counted = "words"
+	}
+	writeAll := func(out io.Writer) error { return grapher.WriteDistributionCSV(out, d) }
+	if csvOutput {
+		if err := writeAll(w); err != nil {
+			return err
+		}
+		return s.writeCSV(io.Discard, "", writeAll)
+	}
+	fmt.Fprint(w, summary)
+	writeHistogram(w, d, counted)
+	if err := s.writeCSV(w, "the ladder lengths", writeAll); err != nil {
+		return err
+	}
+	fmt.Fprint(w, "\n")
+	return nil
+}
+
+// writes the counts of d as rows of #, the longest histogramWidth long;
+// a count that is not zero has at least one
+func writeHistogram(w io.Writer, d grapher.Distribution, counted string) {
+	largest := 0.0
+	for _, count := range d.Counts {
+		largest = max(largest, count)
+	}
+	if largest == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "\nsteps\t%s\n", counted)
+	for steps, count := range d.Counts[1:] {
+		bar := 0
+		if count > 0 {
+			bar = max(int(count / largest * histogramWidth + 0.5), 1)
+		}
+		fmt.Fprintf(tw, "%d\t%.0f\t%s\n", steps + 1, count, strings.Repeat("#", bar))
+	}
+	tw.Flush()
+}
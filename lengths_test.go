@@ -0,0 +1,74 @@
+package main
+
+import (
+		"testing"
+		"bytes"
+		"os"
+		"strings"
+		"path/filepath"
+		"github.com/nerophon/dictdash/dictionary"
+)
+
+func TestLengthsCmd(t *testing.T) {
+	defer func(saved *dictionary.Dictionary, csv bool) { dict, csvOutput = saved, csv }(dict, csvOutput)
+	var err error
+	dict, err = dictionary.Load(strings.NewReader("cold cord card ward warm zzzz"), dictionary.Options{})
+	if err != nil {
+		t.Fatalf("Load(), unexpected error=%v", err)
+	}
+	cases := []struct {
+		target string
+		args []string
+		want string
+	}{
+		{"cold", nil, "" +
+			"Ladders from cold reach 4 other words, 2.50 steps long on average; 1 word of length 4 cannot be reached.\n" +
+			"\n" +
+			"steps  words\n" +
+			"1      1  ##################################################\n" +
+			"2      1  ##################################################\n" +
+			"3      1  ##################################################\n" +
+			"4      1  ##################################################\n" +
+			"\n"},
+		{"4", nil, "" +
+			"Of the 15 pairs of the 6 words of length 4, 10 are joined by ladders, 2.00 steps long on average.\n" +
+			"\n" +
+			"steps  pairs\n" +
+			"1      4  ##################################################\n" +
+			"2      3  ######################################\n" +
+			"3      2  #########################\n" +
+			"4      1  #############\n" +
+			"\n"},
+		{"4", []string{"samples=2", "seed=7"}, "" +
+			"Of the 15 pairs of the 6 words of length 4, "},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		if err = lengthsCmd(&out, c.target, c.args); err != nil {
+			t.Fatalf("lengthsCmd(%s %v), unexpected error=%v", c.target, c.args, err)
+		}
+		if !strings.HasPrefix(out.String(), c.want) {
+			t.Errorf("lengthsCmd(%s %v), want:\n%s\ngot:\n%s", c.target, c.args, c.want, out.String())
+		}
+	}
+
+	var out bytes.Buffer
+	path := filepath.Join(t.TempDir(), "lengths.csv")
+	csvOutput = true
+	if err = lengthsCmd(&out, "4", []string{"csv=" + path}); err != nil {
+		t.Fatalf("lengthsCmd(csv), unexpected error=%v", err)
+	}
+	want := "steps,pairs\n1,4\n2,3\n3,2\n4,1\nnone,5\n"
+	data, _ := os.ReadFile(path)
+	if out.String() != want || string(data) != want {
+		t.Errorf("lengthsCmd(csv), want %q printed and written, got %q and %q", want, out.String(), data)
+	}
+	for _, target := range []string{"cole", "0", "5"} {
+		if err = lengthsCmd(&out, target, nil); err == nil {
+			t.Errorf("lengthsCmd(%s), want error", target)
+		}
+	}
+	if err = lengthsCmd(&out, "4", []string{"5"}); err != errUsage {
+		t.Errorf("lengthsCmd(4 5), want errUsage, got %v", err)
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+		"io"
+		"os"
+		"fmt"
+		"bufio"
+		"strings"
+		"unicode/utf8"
+		"path/filepath"
+)
+
+// the most lines of history kept, in memory and in the history file
+const maxHistory = 1000
+
+// the most completions listed when Tab is pressed on an ambiguous word
+const maxListed = 100
+
+// the width assumed when listing completions
+const listWidth = 80
+
+// reads command lines, letting them be edited when the input is a terminal:
+// left and right move the cursor, up and down recall earlier lines,
+// Tab completes the word being typed, and the usual Emacs control keys
+// (^A ^E ^B ^F ^K ^U ^W) work; otherwise lines are read as they come
+type lineEditor struct {
+	in *bufio.Reader
+	out io.Writer
+	fd int
+	edit bool // false if the input is not a terminal
+	history []string
+	historyPath string // lines are appended to this file if set
+	// the completions of the last word of before, and the byte offset
+	// at which it starts; may be nil
+	complete func(before string) (start int, candidates []string)
+}
+
+func newLineEditor(in *os.File, out io.Writer) *lineEditor {
+	return &lineEditor{in: bufio.NewReader(in), out: out, fd: int(in.Fd()), edit: isTerminal(in)}
+}
+
+// the history file, $DICTDASH_HISTORY or else ~/.dictdash_history;
+// empty if neither can be determined
+func historyPath() string {
+	if path := os.Getenv("DICTDASH_HISTORY"); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".dictdash_history")
+}
+
+// replaces the history with that saved at path, which is then kept up to date;
+// a missing file is not an error
+func (e *lineEditor) loadHistory(path string) error {
+	e.historyPath, e.history = path, nil
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > maxHistory {
+		// rewritten, so that the file does not grow forever
+		lines = lines[len(lines) - maxHistory:]
+		if err = os.WriteFile(path, []byte(strings.Join(lines, "\n") + "\n"), 0600); err != nil {
+			return err
+		}
+	}
+	for _, line := range lines {
+		if line != "" {
+			e.history = append(e.history, line)
+		}
+	}
+	return nil
+}
+
+// adds line to the history, unless it is blank or repeats the last line
+func (e *lineEditor) remember(line string) {
+	if strings.TrimSpace(line) == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history) - maxHistory:]
+	}
+	if e.historyPath == "" {
+		return
+	}
+	file, err := os.OpenFile(e.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return // history is a convenience, and not worth interrupting for
+	}
+	defer file.Close()
+	fmt.Fprintln(file, line)
+}
+
+// shows prompt and returns the next line, without its line ending;
+// returns io.EOF at the end of the input, or when ^D is pressed on an
+// empty line
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	if e.edit {
+		if restore, err := makeRaw(e.fd); err == nil {
+			defer restore()
+			return e.editLine(prompt)
+		}
+	}
+	fmt.Fprint(e.out, prompt)
+	line, err := e.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil // the last line need not end with a newline
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// the state of the line being edited
+type editState struct {
+	prompt string
+	buffer []rune
+	cursor int // an index into buffer
+}
+
+// redraws the line and places the cursor
+func (e *lineEditor) redraw(s *editState) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", s.prompt, string(s.buffer))
+	if back := len(s.buffer) - s.cursor; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+// replaces the line, placing the cursor at its end
+func (s *editState) set(line string) {
+	s.buffer = []rune(line)
+	s.cursor = len(s.buffer)
+}
+
+// reads keys until the line is entered, in raw mode
+func (e *lineEditor) editLine(prompt string) (string, error) {
+	s := &editState{prompt: prompt}
+	recalled := len(e.history) // the history index shown; len(e.history) is the new line
+	var draft string // the new line, kept while earlier lines are shown
+	e.redraw(s)
+	for {
+		key, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch key {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			line := string(s.buffer)
+			e.remember(strings.TrimRight(line, " "))
+			return line, nil
+		case 3: // ^C abandons the line
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", nil
+		case 4: // ^D
+			if len(s.buffer) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			s.delete(s.cursor, s.cursor + 1)
+		case 127, 8: // backspace
+			s.delete(s.cursor - 1, s.cursor)
+		case 1: // ^A
+			s.cursor = 0
+		case 5: // ^E
+			s.cursor = len(s.buffer)
+		case 2: // ^B
+			s.cursor = max(s.cursor - 1, 0)
+		case 6: // ^F
+			s.cursor = min(s.cursor + 1, len(s.buffer))
+		case 11: // ^K
+			s.delete(s.cursor, len(s.buffer))
+		case 21: // ^U
+			s.delete(0, s.cursor)
+		case 23: // ^W deletes the word before the cursor
+			start := s.cursor
+			for start > 0 && s.buffer[start-1] == ' ' {
+				start--
+			}
+			for start > 0 && s.buffer[start-1] != ' ' {
+				start--
+			}
+			s.delete(start, s.cursor)
+		case 16, 14: // ^P, ^N
+			recalled, draft = e.recall(s, recalled, draft, key == 16)
+		case '\t':
+			e.completeWord(s)
+		case 27:
+			switch e.escape() {
+			case 'A':
+				recalled, draft = e.recall(s, recalled, draft, true)
+			case 'B':
+				recalled, draft = e.recall(s, recalled, draft, false)
+			case 'C':
+				s.cursor = min(s.cursor + 1, len(s.buffer))
+			case 'D':
+				s.cursor = max(s.cursor - 1, 0)
+			case 'H':
+				s.cursor = 0
+			case 'F':
+				s.cursor = len(s.buffer)
+			case 'X': // delete
+				s.delete(s.cursor, s.cursor + 1)
+			}
+		default:
+			if key < ' ' {
+				continue
+			}
+			s.buffer = append(s.buffer[:s.cursor], append([]rune{key}, s.buffer[s.cursor:]...)...)
+			s.cursor++
+		}
+		e.redraw(s)
+	}
+}
+
+// removes buffer[from:to], clipped to the buffer
+func (s *editState) delete(from, to int) {
+	from, to = max(from, 0), min(to, len(s.buffer))
+	if from >= to {
+		return
+	}
+	s.buffer = append(s.buffer[:from], s.buffer[to:]...)
+	if s.cursor > to {
+		s.cursor -= to - from
+	} else if s.cursor > from {
+		s.cursor = from
+	}
+}
+
+// shows the previous or next line of history, returning the new index
+// and draft
+func (e *lineEditor) recall(s *editState, recalled int, draft string, previous bool) (int, string) {
+	next := recalled + 1
+	if previous {
+		next = recalled - 1
+	}
+	if next < 0 || next > len(e.history) {
+		return recalled, draft
+	}
+	if recalled == len(e.history) {
+		draft = string(s.buffer)
+	}
+	if next == len(e.history) {
+		s.set(draft)
+	} else {
+		s.set(e.history[next])
+	}
+	return next, draft
+}
+
+// reads the rest of an escape sequence, returning a letter for the
+// arrow and editing keys: A to D for the arrows, H and F for home and end,
+// X for delete, and zero for anything else
+func (e *lineEditor) escape() rune {
+	introducer, _, err := e.in.ReadRune()
+	if err != nil || (introducer != '[' && introducer != 'O') {
+		return 0
+	}
+	var params []rune
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return 0
+		}
+		if r >= '0' && r <= '9' || r == ';' {
+			params = append(params, r)
+			continue
+		}
+		if r != '~' {
+			return r
+		}
+		// keys sent as ESC [ n ~
+		switch string(params) {
+		case "1", "7":
+			return 'H'
+		case "4", "8":
+			return 'F'
+		case "3":
+			return 'X'
+		}
+		return 0
+	}
+}
+
+// completes the word before the cursor as far as its completions agree;
+// a word with one completion is finished with a space, and if nothing
+// can be added the completions are listed
+func (e *lineEditor) completeWord(s *editState) {
+	if e.complete == nil {
+		return
+	}
+	before := string(s.buffer[:s.cursor])
+	start, candidates := e.complete(before)
+	if len(candidates) == 0 {
+		fmt.Fprint(e.out, "\a")
+		return
+	}
+	typed := before[start:]
+	completion := commonPrefix(candidates)
+	if len(candidates) == 1 && !strings.HasSuffix(completion, "/") {
+		completion += " "
+	}
+	if len(completion) > len(typed) {
+		startRune := utf8.RuneCountInString(before[:start])
+		rest := append([]rune(completion), s.buffer[s.cursor:]...)
+		s.buffer = append(s.buffer[:startRune], rest...)
+		s.cursor = startRune + utf8.RuneCountInString(completion)
+		return
+	}
+	e.list(candidates)
+}
+
+// writes candidates in rows below the line being edited
+func (e *lineEditor) list(candidates []string) {
+	fmt.Fprint(e.out, "\r\n")
+	shown := candidates
+	if len(shown) > maxListed {
+		shown = shown[:maxListed]
+	}
+	width := 0
+	for _, c := range shown {
+		width = max(width, len(c) + 2)
+	}
+	perRow := max(listWidth / width, 1)
+	for i, c := range shown {
+		fmt.Fprintf(e.out, "%-*s", width, c)
+		if (i+1) % perRow == 0 || i == len(shown)-1 {
+			fmt.Fprint(e.out, "\r\n")
+		}
+	}
+	if len(candidates) > len(shown) {
+		fmt.Fprintf(e.out, "... and %d more\r\n", len(candidates) - len(shown))
+	}
+}
+
+// the longest prefix shared by every string in words; panics if words is empty
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		i := 0
+		for i < len(prefix) && i < len(w) && prefix[i] == w[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1] // not part of a letter
+	}
+	return prefix
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+		"testing"
+		"io"
+		"os"
+		"bufio"
+		"bytes"
+		"strings"
+		"path/filepath"
+)
+
+func TestEditLine(t *testing.T) {
+	const (
+		left = "\x1b[D"
+		right = "\x1b[C"
+		up = "\x1b[A"
+		down = "\x1b[B"
+		home = "\x1b[H"
+		del = "\x1b[3~"
+	)
+	words := []string{"cold", "cord", "card"}
+	complete := func(before string) (int, []string) {
+		start := strings.LastIndex(before, " ") + 1
+		return start, withPrefix(words, before[start:])
+	}
+	cases := []struct {
+		name string
+		keys string
+		want []string // the lines read
+	}{
+		{"typing", "abc\r", []string{"abc"}},
+		{"cursor", "abc" + left + left + "X" + right + "Y\r", []string{"aXbYc"}},
+		{"home and end", "bc" + home + "a\x05d\r", []string{"abcd"}},
+		{"backspace", "abcd\x7f" + left + "\x7f\r", []string{"ac"}},
+		{"delete", "abc" + home + del + "\x04\r", []string{"c"}},
+		{"kill", "abc def" + left + left + "\x0b\x02\x15\r", []string{"d"}},
+		{"delete word", "search cold  \x17\r", []string{"search "}},
+		{"interrupt", "abc\x03def\r", []string{"", "def"}},
+		{"end of input", "\x04", nil},
+		{"unknown sequence", "a\x1b[5~b\x1b[Zc\r", []string{"abc"}},
+		{"complete one", "search ca\t\r", []string{"search card "}},
+		{"complete common prefix", "search c\to\t\tl\t\r", []string{"search cold "}},
+		{"complete nothing", "search x\t\r", []string{"search x"}},
+		{"history", "one\rtwo\r" + up + up + up + "\r" + up + down + down + "\r", []string{"one", "two", "one", ""}},
+		{"history keeps draft", "one\rdraft" + up + down + "!\r", []string{"one", "draft!"}},
+		{"history skips repeats", "one\rone\r" + up + up + "\r", []string{"one", "one", "one"}},
+	}
+	for _, c := range cases {
+		var out bytes.Buffer
+		e := &lineEditor{in: bufio.NewReader(strings.NewReader(c.keys)), out: &out, complete: complete}
+		var got []string
+		for {
+			line, err := e.editLine("> ")
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("editLine(%s), unexpected error=%v", c.name, err)
+			}
+			got = append(got, line)
+		}
+		if strings.Join(got, "|") != strings.Join(c.want, "|") {
+			t.Errorf("editLine(%s), want=%q, got=%q", c.name, c.want, got)
+		}
+	}
+}
+
+func TestEditLineListsCompletions(t *testing.T) {
+	var out bytes.Buffer
+	e := &lineEditor{
+		in: bufio.NewReader(strings.NewReader("c\t\r")),
+		out: &out,
+		complete: func(before string) (int, []string) { return 0, []string{"cold", "cord"} },
+	}
+	if line, err := e.editLine("> "); err != nil || line != "co" {
+		t.Fatalf("editLine(), want=%q, got=%q, err=%v", "co", line, err)
+	}
+	e.in = bufio.NewReader(strings.NewReader("co\t\r"))
+	out.Reset()
+	e.editLine("> ")
+	if want := "\r\ncold  cord  \r\n"; !strings.Contains(out.String(), want) {
+		t.Errorf("editLine(), want listing %q in %q", want, out.String())
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	e := &lineEditor{in: bufio.NewReader(strings.NewReader("scan\r\rsearch cold warm\r")), out: io.Discard}
+	if err := e.loadHistory(path); err != nil {
+		t.Fatalf("loadHistory(), unexpected error=%v", err)
+	}
+	for i := 0; i < 3; i++ {
+		e.editLine("> ")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "scan\nsearch cold warm\n" {
+		t.Errorf("history file, want=%q, got=%q, err=%v", "scan\nsearch cold warm\n", data, err)
+	}
+	again := &lineEditor{}
+	if err := again.loadHistory(path); err != nil || strings.Join(again.history, "|") != "scan|search cold warm" {
+		t.Errorf("loadHistory(), got=%q, err=%v", again.history, err)
+	}
+
+	long := strings.Repeat("line\n", maxHistory + 10)
+	os.WriteFile(path, []byte(long), 0600)
+	if err := again.loadHistory(path); err != nil {
+		t.Fatalf("loadHistory(), unexpected error=%v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if len(again.history) != maxHistory || strings.Count(string(data), "\n") != maxHistory {
+		t.Errorf("loadHistory(), want %d lines, got %d in memory and %d in file", maxHistory, len(again.history), strings.Count(string(data), "\n"))
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+		"io"
+		"os"
+		"fmt"
+		"strconv"
+		"strings"
+		"github.com/nerophon/dictdash/grapher"
+)
+
+// the central and lengths commands search from every word, unless there
+// are more than maxExactWords, when they search from defaultSamples words
+// chosen at random
+const maxExactWords = 2000
+const defaultSamples = 500
+
+// when true, the central and lengths commands print CSV instead of tables,
+// for other programs to read
+var csvOutput bool
+
+// the options shared by the commands that search from many words:
+// samples=k (or all), seed=s and csv=file
+type sampling struct {
+	opts grapher.SampleOptions // Samples is -1 until given
+	csvPath string
+}
+
+func newSampling() sampling {
+	return sampling{opts: grapher.SampleOptions{Samples: -1, Seed: 1}}
+}
+
+// reads arg if it is one of the options, returning false if it is not
+func (s *sampling) parse(arg string) (bool, error) {
+	key, value, _ := strings.Cut(arg, "=")
+	var err error
+	switch {
+	case key == "samples" && value == "all":
+		s.opts.Samples = 0
+	case key == "samples":
+		s.opts.Samples, err = strconv.Atoi(value)
+		if err != nil || s.opts.Samples <= 0 {
+			return true, fmt.Errorf("%s is not a number of samples; please give a positive number or all", value)
+		}
+	case key == "seed":
+		s.opts.Seed, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return true, fmt.Errorf("%s is not a seed", value)
+		}
+	case key == "csv" && value != "":
+		s.csvPath = value
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// the options for searching among the given number of words
+func (s *sampling) among(words int) grapher.SampleOptions {
+	opts := s.opts
+	if opts.Samples < 0 {
+		opts.Samples = 0
+		if words > maxExactWords {
+			opts.Samples = defaultSamples
+		}
+	}
+	return opts
+}
+
+// describes an estimate from searches from some of the given number of
+// words, as a clause to end a sentence; empty if every word is searched from
+func (s *sampling) describe(words int) string {
+	opts := s.among(words)
+	if opts.Samples == 0 || opts.Samples >= words {
+		return ""
+	}
+	return fmt.Sprintf(", estimated from %d of them with seed %d", opts.Samples, opts.Seed)
+}
+
+// writes what to the file given by csv=, if any, and reports it to w
+func (s *sampling) writeCSV(w io.Writer, what string, write func(io.Writer) error) error {
+	if s.csvPath == "" {
+		return nil
+	}
+	file, err := os.Create(s.csvPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err = write(file); err != nil {
+		return fmt.Errorf("writing %s failed: %w", what, err)
+	}
+	fmt.Fprintf(w, "\nWrote %s to %s.\n", what, s.csvPath)
+	return nil
+}
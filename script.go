@@ -0,0 +1,221 @@
+package main
+
+import (
+		"io"
+		"os"
+		"fmt"
+		"sort"
+		"bufio"
+		"errors"
+		"strings"
+		"text/tabwriter"
+		"github.com/nerophon/dictdash/search"
+)
+
+// scripts are files of REPL commands, one per line, run by
+// dictdash -script; # starts a comment, at the start of a line or after
+// a space, and errors are reported with the line that caused them
+
+// when true, a script stops at the first command that fails
+var stopOnError bool
+
+// when true, every search shows its statistics, as if given --stats
+var alwaysStats bool
+
+// an option changed by the set command
+type setting struct {
+	name string
+	values string // the values accepted, for help
+	get func() string
+	set func(args []string) error
+}
+
+// the options of the set command, in the order listed
+var settings = []setting{
+	{
+		name: "output",
+		values: "auto|plain|brackets|colour",
+		get: func() string { return nameOf(ladderStyles, ladderOutput) },
+		set: func(args []string) error {
+			if len(args) != 1 {
+				return errUsage
+			}
+			return outputCmd(args[0])
+		},
+	},
+	{
+		name: "tiebreak",
+		values: "first|lex|freq|h|random [<seed>]",
+		get: func() string {
+			name := nameOf(tieBreaks, searchOptions.TieBreak)
+			if searchOptions.TieBreak == search.TieRandom {
+				name += fmt.Sprint(" ", searchOptions.Seed)
+			}
+			return name
+		},
+		set: func(args []string) error {
+			if len(args) < 1 || len(args) > 2 {
+				return errUsage
+			}
+			return tieBreakCmd(args[0], args[1:])
+		},
+	},
+	{
+		name: "engine",
+		values: "astar|dijkstra|bfs",
+		get: func() string { return nameOf(engines, searchOptions.Engine) },
+		set: func(args []string) error { return parseChoice(args, engines, &searchOptions.Engine) },
+	},
+	{
+		name: "cost",
+		values: "unit|moves",
+		get: func() string { return nameOf(costModels, searchOptions.Cost) },
+		set: func(args []string) error { return parseChoice(args, costModels, &searchOptions.Cost) },
+	},
+	{
+		name: "stats",
+		values: "on|off",
+		get: func() string { return onOff(alwaysStats) },
+		set: func(args []string) error { return parseOnOff(args, &alwaysStats) },
+	},
+	{
+		name: "csv",
+		values: "on|off",
+		get: func() string { return onOff(csvOutput) },
+		set: func(args []string) error { return parseOnOff(args, &csvOutput) },
+	},
+	{
+		name: "stop",
+		values: "on|off",
+		get: func() string { return onOff(stopOnError) },
+		set: func(args []string) error { return parseOnOff(args, &stopOnError) },
+	},
+}
+
+// the names of the settings, for completion
+func settingNames() []string {
+	names := make([]string, len(settings))
+	for i, s := range settings {
+		names[i] = s.name
+	}
+	return names
+}
+
+// runs the set command: with no arguments it lists every setting,
+// and otherwise changes the one named by args[0]
+func setCmd(args []string) error {
+	if len(args) == 0 {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		for _, s := range settings {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.name, s.get(), s.values)
+		}
+		fmt.Fprint(tw, "\n")
+		return tw.Flush()
+	}
+	for _, s := range settings {
+		if s.name == args[0] {
+			err := s.set(args[1:])
+			if err == errUsage {
+				return fmt.Errorf("usage: set %s %s", s.name, s.values)
+			}
+			return err
+		}
+	}
+	return fmt.Errorf("there is no setting %s; please use %s", args[0], strings.Join(settingNames(), ", "))
+}
+
+// the key of value in names
+func nameOf[V comparable](names map[string]V, value V) string {
+	keys := make([]string, 0, len(names))
+	for k := range names {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys) // in case of aliases, for a stable answer
+	for _, k := range keys {
+		if names[k] == value {
+			return k
+		}
+	}
+	return "?"
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// sets b from an argument of on or off
+func parseOnOff(args []string, b *bool) error {
+	if len(args) != 1 || (args[0] != "on" && args[0] != "off") {
+		return errUsage
+	}
+	*b = args[0] == "on"
+	return nil
+}
+
+// sets v from an argument naming one of names
+func parseChoice[V comparable](args []string, names map[string]V, v *V) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	value, ok := names[args[0]]
+	if !ok {
+		return errUsage
+	}
+	*v = value
+	return nil
+}
+
+// line with any comment removed; a comment starts at a # at the start of
+// the line or after a space or tab
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		if line[i] == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// runs each line of script as a command, writing errors to errs prefixed
+// by name and the line number; stops early at a quit command, or at the
+// first failure when stopOnError is set; returns the number of lines that
+// failed, and any error reading the script
+func runScript(commands *registry, script io.Reader, name string, errs io.Writer) (failures int, err error) {
+	scanner := bufio.NewScanner(script)
+	for n := 1; scanner.Scan(); n++ {
+		err := commands.execute(scanner.Text())
+		if errors.Is(err, errQuit) {
+			break
+		}
+		if err != nil {
+			failures++
+			fmt.Fprintf(errs, "%s:%d: %v\n", name, n, err)
+			if stopOnError {
+				break
+			}
+		}
+	}
+	return failures, scanner.Err()
+}
+
+// runs the script at path, then exits; the exit status is 1 if any
+// command failed, and 2 if the script could not be read
+func runScriptFile(commands *registry, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer file.Close()
+	failures, err := runScript(commands, file, path, os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "reading %s failed: %v\n", path, err)
+		os.Exit(2)
+	}
+	if failures > 0 {
+		os.Exit(1)
+	}
+}
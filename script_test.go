@@ -0,0 +1,93 @@
+package main
+
+import (
+		"testing"
+		"errors"
+		"strings"
+		"bytes"
+		"github.com/nerophon/dictdash/search"
+)
+
+func TestRunScript(t *testing.T) {
+	var ran []string
+	r := newRegistry()
+	r.add(&command{
+		name: "ok",
+		args: []argSpec{{name: "x", kind: argText, optional: true, repeated: true}},
+		run: func(args []string) error {
+			ran = append(ran, strings.Join(args, " "))
+			return nil
+		},
+	})
+	r.add(&command{name: "fail", run: func(args []string) error { return errors.New("failed") }})
+	r.add(&command{name: "quit", run: func(args []string) error { return errQuit }})
+	script := "# a comment\n" +
+		"ok one # two\n" +
+		"\n" +
+		"fail\n" +
+		"ok a#b\n" +
+		"unknown\n" +
+		"ok three\n" +
+		"quit\n" +
+		"ok four\n"
+	cases := []struct {
+		stop bool
+		ran string
+		failures int
+		errs string
+	}{
+		{false, "one|a#b|three", 2, "s.dd:4: failed\ns.dd:6: command unknown not understood; typing \"help\" will show a command list\n"},
+		{true, "one", 1, "s.dd:4: failed\n"},
+	}
+	defer func(saved bool) { stopOnError = saved }(stopOnError)
+	for _, c := range cases {
+		ran, stopOnError = nil, c.stop
+		var errs bytes.Buffer
+		failures, err := runScript(r, strings.NewReader(script), "s.dd", &errs)
+		if err != nil || failures != c.failures || strings.Join(ran, "|") != c.ran || errs.String() != c.errs {
+			t.Errorf("runScript(stop=%v), want=%d %q %q, got=%d %q %q, err=%v",
+				c.stop, c.failures, c.ran, c.errs, failures, strings.Join(ran, "|"), errs.String(), err)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	defer func(output ladderStyle, stats bool) { ladderOutput, alwaysStats = output, stats }(ladderOutput, alwaysStats)
+	defer func(saved search.Options) { searchOptions = saved }(searchOptions)
+	r := newCommands()
+	cases := []struct {
+		line string
+		err string
+	}{
+		{"set output brackets", ""},
+		{"set stats on", ""},
+		{"set output", "usage: set output auto|plain|brackets|colour"},
+		{"set stats maybe", "usage: set stats on|off"},
+		{"set output sepia", "unknown output style sepia; please use auto, plain, brackets or colour"},
+		{"set colour on", "there is no setting colour; please use output, tiebreak, engine, cost, stats, csv, stop"},
+		{"set engine dijkstra", ""},
+		{"set cost moves", ""},
+		{"set engine depth", "usage: set engine astar|dijkstra|bfs"},
+		{"set cost", "usage: set cost unit|moves"},
+	}
+	for _, c := range cases {
+		got := ""
+		if err := r.execute(c.line); err != nil {
+			got = err.Error()
+		}
+		if got != c.err {
+			t.Errorf("execute(%q), want=%q, got=%q", c.line, c.err, got)
+		}
+	}
+	if searchOptions.Engine != search.EngineDijkstra || searchOptions.Cost != search.CostWeighted {
+		t.Errorf("set, want dijkstra and moves, got=%+v", searchOptions)
+	}
+	if ladderOutput != styleBrackets || !alwaysStats {
+		t.Errorf("set, want brackets and stats, got=%v %v", ladderOutput, alwaysStats)
+	}
+	for _, s := range settings {
+		if s.name == "output" && s.get() != "brackets" {
+			t.Errorf("set output, want brackets, got=%s", s.get())
+		}
+	}
+}
@@ -5,11 +5,44 @@ MIT Licence, to be reproduced in full if published
 */
 package search
 
-import "container/heap"
+import (
+		"errors"
+		"time"
+		"context"
+)
 
 // the A* pathfinding algorithm
 
-// GraphNode is an interface which allows A* searching on arbitrary objects
+// the numeric types usable as movement costs;
+// costs must never be negative
+type Cost interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Adjacency is the part of a Graph needed by unweighted searches
+type Adjacency[N comparable] interface {
+
+	// nodes to which a direct no-hops path exists
+	Neighbours(node N) []N
+}
+
+// Graph is an interface which allows searching over any comparable node type,
+// such as a pointer or an integer ID, without boxing nodes in interfaces
+type Graph[N comparable, C Cost] interface {
+	Adjacency[N]
+
+	// the exact movement cost to a neighbouring node
+	Cost(from, to N) C
+
+	// heuristic that estimates the total travel cost from node to target;
+	// the search only guarantees a shortest path if it never overestimates
+	Estimate(node, target N) C
+}
+
+// GraphNode is an interface which allows A* searching on arbitrary objects;
+// the Nodes adapter lets it be used wherever a Graph is expected
 type GraphNode interface {
 
 	// GraphNodes to which a direct no-hops path exists
@@ -22,32 +55,34 @@ type GraphNode interface {
 	EstimatedTargetCost(to GraphNode) int
 }
 
-// a wrapper to store A* data for a GraphNode.
-type aStarNode struct {
-	graphNode GraphNode
-	parent *aStarNode
-	open bool
-	closed bool
-	cost int // actual cost of reaching this node from the start
-	rank int // lower is better (i.e. 1 is better than 2)
-	index int // in the priority queue
+// Nodes adapts GraphNodes to the Graph interface;
+// it holds no state, so the zero value is ready to use
+type Nodes struct{}
+
+func (Nodes) Neighbours(node GraphNode) []GraphNode {
+	return node.GetNeighbors()
 }
 
-// a collection of aStarNodes keyed by GraphNodes
-// needed for quick reference
-type nodeMap map[GraphNode]*aStarNode
+func (Nodes) Cost(from, to GraphNode) int {
+	return from.ActualNeighborCost(to)
+}
 
-// get gets the GraphNode wrapped in an aStarNode,
-// instantiating if required.
-func (nm nodeMap) get(gn GraphNode) *aStarNode {
-	node, ok := nm[gn]
-	if !ok {
-		node = &aStarNode{
-			graphNode: gn,
-		}
-		nm[gn] = node
-	}
-	return node
+func (Nodes) Estimate(node, target GraphNode) int {
+	return node.EstimatedTargetCost(target)
+}
+
+// a wrapper to store A* data for a node.
+type aStarNode[N comparable, C Cost] struct {
+	graphNode N
+	parent *aStarNode[N, C]
+	open bool
+	closed bool
+	cost C // actual cost of reaching this node from the start
+	depth int // number of hops from the start
+	rank C // lower is better (i.e. 1 is better than 2)
+	tie C // breaks ties in rank; lower is better
+	seq uint64 // breaks ties in rank and tie; lower is better
+	index int // in the priority queue
 }
 
 // this search is a simple special case of single-pair shortest-path,
@@ -56,77 +91,116 @@ func (nm nodeMap) get(gn GraphNode) *aStarNode {
 // where the heuristic is the theoretic min transforms,
 // ignoring whether or not the word is in the dictionary;
 // this heuristic is admissable because it cannot overestimate
-// 
+//
 // panics if graph is nil
 // func Search(src string, dst string, graph WordGraph) {
 // 	fmt.Println("Sorry, command not yet implemented.\n")
 // }
 
+// limits on a search; the zero value means no limits
+type Options struct {
+	// the maximum number of nodes to expand before aborting
+	MaxExpanded int
+	// the maximum number of hops in a path;
+	// nodes further from the start are not explored
+	MaxDepth int
+	// which of several shortest paths is returned
+	TieBreak TieBreak
+	// seeds TieRandom, so that its choices can be repeated
+	Seed int64
+	// the algorithm used, A* by default
+	Engine Engine
+	// how steps are costed, by the graph's Cost by default
+	Cost CostModel
+	// if not nil, receives statistics about the search;
+	// left nil, the search skips gathering them
+	Stats *Stats
+}
+
+// how a search ended
+type Outcome int
+
+const (
+	NotFound Outcome = iota // every reachable node was searched
+	Found
+	Aborted // stopped early; a path may still exist
+)
+
+// errors explaining an Aborted outcome,
+// alongside context.Canceled and context.DeadlineExceeded
+var (
+	ErrMaxExpanded = errors.New("search: expanded node limit reached")
+	ErrMaxDepth = errors.New("search: no path within depth limit")
+)
+
+// counters gathered during a search that asks for them with Options.Stats,
+// which are partial if the search was aborted
+type Stats struct {
+	Expanded int // nodes taken from the queue
+	Generated int // nodes pushed onto the queue, including the start
+	Reopened int // expanded nodes queued again after a cheaper path to them was found
+	PeakQueue int // the largest size the queue reached
+	Depth int // the deepest node expanded, in hops from the start
+	Elapsed time.Duration // wall time spent searching
+}
+
+// starts timing a search, returning the function that records its
+// Elapsed time when the search ends
+func (stats *Stats) timer() func() {
+	start := time.Now()
+	return func() {
+		stats.Elapsed = time.Since(start)
+	}
+}
+
+// the outcome of a search;
+// Path and Distance are only set when Outcome is Found,
+// and Err is only set when Outcome is Aborted
+type Result[N comparable, C Cost] struct {
+	Path []N
+	Distance C
+	Outcome Outcome
+	Err error
+}
+
 // calculates the shortest path between two GraphNodes;
 // if no path is found, found will be false
 func Path(from, to GraphNode) (path []GraphNode, distance int, found bool) {
-	nodeMap := nodeMap{}
-	queue := &PriorityQueue{}
-	heap.Init(queue)
-	fromNode := nodeMap.get(from)
-	fromNode.open = true
-	heap.Push(queue, (*item)(fromNode))
-	for {
-		if queue.Len() == 0 {
-			// there's no path, return found false
-			return
-		}
-		// priority queue does its magic:
-		current := (*aStarNode)(heap.Pop(queue).(*item))
-		current.open = false
-		current.closed = true
-
-		if current == nodeMap.get(to) {
-			// found a path to the goal!
-			reversePath := []GraphNode{}
-			curr := current
-			for curr != nil {
-				reversePath = append(reversePath, curr.graphNode)
-				curr = curr.parent
-			}
-			// reverse path to get from --> to
-			path = make([]GraphNode, len(reversePath))
-			j := 0
-			for i := len(reversePath)-1; i >= 0; i-- {
-				path[j] = reversePath[i]
-				j++
-			}
-			return path, current.cost, true
-		}
-
-
-		for _, neighbor := range current.graphNode.GetNeighbors() {
-			cost := current.cost + current.graphNode.ActualNeighborCost(neighbor)
-			neighborNode := nodeMap.get(neighbor)
-			if cost < neighborNode.cost {
-				// this branch is for handling nodes
-				// that have already been assessed in previous iterations
-				// where the new cost is cheaper
-				if neighborNode.open {
-					heap.Remove(queue, neighborNode.index)
-				}
-				neighborNode.open = false
-				neighborNode.closed = false
-			}
-			if !neighborNode.open && !neighborNode.closed {
-				neighborNode.cost = cost
-				neighborNode.open = true
-				neighborNode.rank = cost + neighbor.EstimatedTargetCost(to)
-				neighborNode.parent = current
-				heap.Push(queue, (*item)(neighborNode))
-			}
-		}
-	}
+	result := PathContext(context.Background(), from, to, Options{})
+	return result.Path, result.Distance, result.Outcome == Found
 }
 
+// calculates the shortest path between two GraphNodes,
+// aborting if ctx is done or a limit in opts is reached
+func PathContext(ctx context.Context, from, to GraphNode, opts Options) Result[GraphNode, int] {
+	return AStar[GraphNode, int](ctx, Nodes{}, from, to, opts)
+}
 
+// a Graph whose heuristic always estimates zero
+type zeroEstimate[N comparable, C Cost] struct {
+	Graph[N, C]
+}
 
+func (zeroEstimate[N, C]) Estimate(node, target N) (zero C) {
+	return
+}
 
+// calculates the shortest path between two nodes of g,
+// ignoring its heuristic, so that the result is optimal
+// even where the heuristic is inadmissible
+func Dijkstra[N comparable, C Cost](ctx context.Context, g Graph[N, C], from, to N, opts Options) Result[N, C] {
+	return AStar[N, C](ctx, zeroEstimate[N, C]{g}, from, to, opts)
+}
 
+// calculates the shortest path between two nodes of g,
+// aborting if ctx is done or a limit in opts is reached
+func AStar[N comparable, C Cost](ctx context.Context, g Graph[N, C], from, to N, opts Options) Result[N, C] {
+	return AStarMulti(ctx, g, []N{from}, []N{to}, opts)
+}
 
-
+// calculates the shortest path from any node in from to any node in to,
+// where the heuristic is the smallest estimate to any of the targets;
+// the search stops at the first target reached, which is a nearest one
+func AStarMulti[N comparable, C Cost](ctx context.Context, g Graph[N, C], from, to []N, opts Options) Result[N, C] {
+	return new(Workspace[N, C]).AStarMulti(ctx, g, from, to, opts)
+}
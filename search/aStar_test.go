@@ -3,6 +3,7 @@ package search
 import (
 		"testing"
 		"reflect"
+		"context"
 )
 
 type TestNode struct {
@@ -78,7 +79,7 @@ func TestPath(t *testing.T) {
 			t.Errorf("TestPath: distWant=%d, distGot=%d", c.distWant, distGot)
 		} 
 		if c.foundWant != foundGot {
-			t.Errorf("TestPath: foundWant=%b, foundGot=%b", c.foundWant, foundGot)
+			t.Errorf("TestPath: foundWant=%t, foundGot=%t", c.foundWant, foundGot)
 		} 
 	}	
 }
@@ -96,3 +97,157 @@ func TestPath(t *testing.T) {
 
 
 
+
+// returns a chain of n TestNodes, each linked to the next and previous
+func testChain(n int) (nodes []GraphNode) {
+	nodes = make([]GraphNode, n)
+	for i := range nodes {
+		nodes[i] = GraphNode(NewTestNode(string(rune('a' + i))))
+	}
+	for i := range nodes {
+		if i > 0 {
+			nodes[i].(*TestNode).Neighbours = append(nodes[i].(*TestNode).Neighbours, nodes[i-1])
+		}
+		if i < n-1 {
+			nodes[i].(*TestNode).Neighbours = append(nodes[i].(*TestNode).Neighbours, nodes[i+1])
+		}
+	}
+	return
+}
+
+func TestPathContext(t *testing.T) {
+	chain := testChain(6)
+	isolated := GraphNode(NewTestNode("z"))
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	cases := []struct {
+		name string
+		ctx context.Context
+		to GraphNode
+		opts Options
+		outcomeWant Outcome
+		errWant error
+		expandedWant int
+	}{
+		{"unlimited", context.Background(), chain[5], Options{}, Found, nil, 6},
+		{"no path", context.Background(), isolated, Options{}, NotFound, nil, 6},
+		{"cancelled", cancelled, chain[5], Options{}, Aborted, context.Canceled, 0},
+		{"expansion budget", context.Background(), chain[5], Options{MaxExpanded: 3}, Aborted, ErrMaxExpanded, 3},
+		{"budget not reached", context.Background(), chain[5], Options{MaxExpanded: 6}, Found, nil, 6},
+		{"depth limit", context.Background(), chain[5], Options{MaxDepth: 4}, Aborted, ErrMaxDepth, 5},
+		{"depth sufficient", context.Background(), chain[5], Options{MaxDepth: 5}, Found, nil, 6},
+	}
+	for _, c := range cases {
+		var stats Stats
+		c.opts.Stats = &stats
+		got := PathContext(c.ctx, chain[0], c.to, c.opts)
+		if got.Outcome != c.outcomeWant {
+			t.Errorf("TestPathContext(%s): outcomeWant=%d, outcomeGot=%d", c.name, c.outcomeWant, got.Outcome)
+		}
+		if got.Err != c.errWant {
+			t.Errorf("TestPathContext(%s): errWant=%v, errGot=%v", c.name, c.errWant, got.Err)
+		}
+		if stats.Expanded != c.expandedWant {
+			t.Errorf("TestPathContext(%s): expandedWant=%d, expandedGot=%d", c.name, c.expandedWant, stats.Expanded)
+		}
+		if (got.Outcome == Found) != (len(got.Path) == 6) {
+			t.Errorf("TestPathContext(%s): unexpected path=%v", c.name, got.Path)
+		}
+	}
+}
+
+// a node with explicit edge costs and a fixed estimate to any target,
+// for exercising heuristics that are admissible but inconsistent
+type weightedNode struct {
+	name string
+	edges map[GraphNode]int
+	order []GraphNode
+	estimate int
+}
+func newWeightedNode(name string, estimate int) *weightedNode {
+	return &weightedNode{name: name, edges: map[GraphNode]int{}, estimate: estimate}
+}
+func (node *weightedNode) String() string {
+	return node.name
+}
+func (node *weightedNode) link(to *weightedNode, cost int) {
+	node.edges[to], to.edges[node] = cost, cost
+	node.order = append(node.order, to)
+	to.order = append(to.order, node)
+}
+func (node *weightedNode) GetNeighbors() []GraphNode {
+	return node.order
+}
+func (node *weightedNode) ActualNeighborCost(to GraphNode) int {
+	return node.edges[to]
+}
+func (node *weightedNode) EstimatedTargetCost(to GraphNode) int {
+	return node.estimate
+}
+
+func TestPathStats(t *testing.T) {
+	// a star: the centre is linked to three leaves
+	centre := GraphNode(NewTestNode("a"))
+	leaves := []GraphNode{NewTestNode("b"), NewTestNode("c"), NewTestNode("d")}
+	centre.(*TestNode).Neighbours = leaves
+	for _, leaf := range leaves {
+		leaf.(*TestNode).Neighbours = []GraphNode{centre}
+	}
+	// s-b is expensive but looks cheap, so b is expanded before a
+	// and must be reopened once the cheaper route via a is found
+	s, a, b, goal := newWeightedNode("s", 0), newWeightedNode("a", 4), newWeightedNode("b", 0), newWeightedNode("t", 0)
+	s.link(a, 1)
+	s.link(b, 3)
+	a.link(b, 1)
+	b.link(goal, 3)
+	cases := []struct {
+		from, to GraphNode
+		distWant int
+		statsWant Stats
+	}{
+		{centre, leaves[2], 1, Stats{Expanded: 2, Generated: 4, PeakQueue: 3, Depth: 1}},
+		{s, goal, 5, Stats{Expanded: 5, Generated: 6, Reopened: 1, PeakQueue: 2, Depth: 3}},
+	}
+	for _, c := range cases {
+		var stats Stats
+		got := PathContext(context.Background(), c.from, c.to, Options{Stats: &stats})
+		if got.Distance != c.distWant {
+			t.Errorf("TestPathStats(%v, %v): distWant=%d, distGot=%d", c.from, c.to, c.distWant, got.Distance)
+		}
+		stats.Elapsed = 0 // varies between runs
+		if stats != c.statsWant {
+			t.Errorf("TestPathStats(%v, %v): statsWant=%+v, statsGot=%+v", c.from, c.to, c.statsWant, stats)
+		}
+		// and the same search without asking for them still finds the path
+		if plain := PathContext(context.Background(), c.from, c.to, Options{}); !reflect.DeepEqual(plain, got) {
+			t.Errorf("TestPathStats(%v, %v): without stats want=%+v, got=%+v", c.from, c.to, got, plain)
+		}
+	}
+}
+
+func TestAStarMulti(t *testing.T) {
+	chain := testChain(6)
+	cases := []struct {
+		name string
+		from, to []GraphNode
+		pathWant []GraphNode
+		outcomeWant Outcome
+	}{
+		{"single", chain[:1], chain[5:], chain, Found},
+		{"nearest target", chain[:1], []GraphNode{chain[5], chain[2]}, chain[:3], Found},
+		{"nearest source", []GraphNode{chain[0], chain[4]}, chain[5:], chain[4:], Found},
+		{"source is target", []GraphNode{chain[1], chain[3]}, []GraphNode{chain[3]}, chain[3:4], Found},
+		{"repeated source", []GraphNode{chain[0], chain[0]}, chain[1:2], chain[:2], Found},
+		{"no targets", chain[:1], nil, nil, NotFound},
+		{"no sources", nil, chain[:1], nil, NotFound},
+	}
+	for _, c := range cases {
+		got := AStarMulti[GraphNode, int](context.Background(), Nodes{}, c.from, c.to, Options{})
+		if !reflect.DeepEqual(got.Path, c.pathWant) {
+			t.Errorf("TestAStarMulti(%s): pathWant=%v, pathGot=%v", c.name, c.pathWant, got.Path)
+		}
+		if got.Outcome != c.outcomeWant {
+			t.Errorf("TestAStarMulti(%s): outcomeWant=%d, outcomeGot=%d", c.name, c.outcomeWant, got.Outcome)
+		}
+	}
+}
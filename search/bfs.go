@@ -0,0 +1,100 @@
+package search
+
+import (
+		"context"
+)
+
+// the breadth-first search algorithm
+
+// calculates the path with the fewest hops between two nodes of g,
+// ignoring any costs and heuristic; where every edge costs the same
+// this finds a shortest path without the overhead of a priority queue;
+// aborts if ctx is done or a limit in opts is reached
+func BFS[N comparable](ctx context.Context, g Adjacency[N], from, to N, opts Options) Result[N, int] {
+	return BFSMulti(ctx, g, []N{from}, []N{to}, opts)
+}
+
+// calculates the path with the fewest hops from any node in from to any
+// node in to, as BFS does
+func BFSMulti[N comparable](ctx context.Context, g Adjacency[N], from, to []N, opts Options) (result Result[N, int]) {
+	stats := opts.Stats
+	if stats != nil {
+		*stats = Stats{}
+		defer stats.timer()()
+	}
+	targets := make(map[N]bool, len(to))
+	for _, target := range to {
+		targets[target] = true
+	}
+	parents := map[N]N{}
+	depths := map[N]int{}
+	var queue []N
+	for _, source := range from {
+		if _, seen := depths[source]; !seen {
+			depths[source] = 0
+			queue = append(queue, source)
+		}
+	}
+	if stats != nil {
+		stats.Generated, stats.PeakQueue = len(queue), len(queue)
+	}
+	done := ctx.Done()
+	pruned := false
+	expanded := 0 // counted even without stats, for MaxExpanded
+	for len(queue) > 0 && len(targets) > 0 {
+		select {
+		case <-done:
+			result.Outcome, result.Err = Aborted, ctx.Err()
+			return
+		default:
+		}
+		if opts.MaxExpanded > 0 && expanded >= opts.MaxExpanded {
+			result.Outcome, result.Err = Aborted, ErrMaxExpanded
+			return
+		}
+		current := queue[0]
+		queue = queue[1:]
+		depth := depths[current]
+		expanded++
+		if stats != nil {
+			stats.Expanded = expanded
+			stats.Depth = max(stats.Depth, depth)
+		}
+
+		if targets[current] {
+			// sources have depth zero, so the path is filled back to one
+			result.Path = make([]N, depth+1)
+			for i := depth; i >= 0; i-- {
+				result.Path[i] = current
+				current = parents[current]
+			}
+			result.Distance = depth
+			result.Outcome = Found
+			return
+		}
+
+		if opts.MaxDepth > 0 && depth >= opts.MaxDepth {
+			pruned = true
+			continue
+		}
+		for _, neighbour := range g.Neighbours(current) {
+			if _, seen := depths[neighbour]; seen {
+				continue
+			}
+			depths[neighbour] = depth + 1
+			parents[neighbour] = current
+			queue = append(queue, neighbour)
+			if stats != nil {
+				stats.Generated++
+			}
+		}
+		if stats != nil {
+			stats.PeakQueue = max(stats.PeakQueue, len(queue))
+		}
+	}
+	// there's no path, unless one was pruned by depth
+	if pruned {
+		result.Outcome, result.Err = Aborted, ErrMaxDepth
+	}
+	return
+}
@@ -0,0 +1,133 @@
+package search
+
+import (
+		"testing"
+		"reflect"
+		"context"
+)
+
+// a width x height grid of integer node IDs, 4-connected,
+// with some cells walled off; steps cost 1, or weight if set
+type testGrid struct {
+	width, height int
+	walls map[int]bool
+	weight float64
+}
+
+func (g testGrid) Neighbours(node int) (neighbours []int) {
+	x, y := node%g.width, node/g.width
+	candidates := [][2]int{{x, y-1}, {x-1, y}, {x+1, y}, {x, y+1}}
+	for _, c := range candidates {
+		if c[0] < 0 || c[0] >= g.width || c[1] < 0 || c[1] >= g.height {
+			continue
+		}
+		if id := c[1]*g.width + c[0]; !g.walls[id] {
+			neighbours = append(neighbours, id)
+		}
+	}
+	return
+}
+
+func (g testGrid) Cost(from, to int) float64 {
+	if g.weight > 0 {
+		return g.weight
+	}
+	return 1
+}
+
+// manhattan distance, which is admissible for unit or greater costs
+func (g testGrid) Estimate(node, target int) float64 {
+	dx, dy := node%g.width - target%g.width, node/g.width - target/g.width
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	return float64(dx + dy)
+}
+
+// a 4x3 grid:
+//  0  1  2  3
+//  4  #  #  7
+//  8  9 10 11
+// with cell 3 reachable, and a separate wall-enclosed grid for no-path cases
+var testWalls = testGrid{width: 4, height: 3, walls: map[int]bool{5: true, 6: true}}
+
+func TestBFS(t *testing.T) {
+	cases := []struct {
+		from, to int
+		opts Options
+		pathWant []int
+		outcomeWant Outcome
+	}{
+		{0, 0, Options{}, []int{0}, Found},
+		{0, 1, Options{}, []int{0, 1}, Found},
+		{0, 10, Options{}, []int{0, 4, 8, 9, 10}, Found},
+		{0, 10, Options{MaxDepth: 3}, nil, Aborted},
+		{0, 10, Options{MaxExpanded: 2}, nil, Aborted},
+		{0, 5, Options{}, nil, NotFound}, // a wall is never reached
+	}
+	for _, c := range cases {
+		got := BFS[int](context.Background(), testWalls, c.from, c.to, c.opts)
+		if !reflect.DeepEqual(got.Path, c.pathWant) {
+			t.Errorf("TestBFS(%d, %d): pathWant=%v, pathGot=%v", c.from, c.to, c.pathWant, got.Path)
+		}
+		if got.Outcome != c.outcomeWant {
+			t.Errorf("TestBFS(%d, %d): outcomeWant=%d, outcomeGot=%d", c.from, c.to, c.outcomeWant, got.Outcome)
+		}
+		if got.Outcome == Found && got.Distance != len(c.pathWant)-1 {
+			t.Errorf("TestBFS(%d, %d): distWant=%d, distGot=%d", c.from, c.to, len(c.pathWant)-1, got.Distance)
+		}
+	}
+}
+
+func TestGenericSearches(t *testing.T) {
+	weighted := testWalls
+	weighted.weight = 2.5
+	cases := []struct {
+		name string
+		search func(g Graph[int, float64], from, to int) Result[int, float64]
+		g Graph[int, float64]
+		distWant float64
+	}{
+		{"AStar", func(g Graph[int, float64], from, to int) Result[int, float64] {
+			return AStar(context.Background(), g, from, to, Options{})
+		}, testWalls, 5},
+		{"Dijkstra", func(g Graph[int, float64], from, to int) Result[int, float64] {
+			return Dijkstra(context.Background(), g, from, to, Options{})
+		}, testWalls, 5},
+		{"weighted AStar", func(g Graph[int, float64], from, to int) Result[int, float64] {
+			return AStar(context.Background(), g, from, to, Options{})
+		}, weighted, 12.5},
+	}
+	for _, c := range cases {
+		// from the top right corner to the bottom left, around the wall
+		got := c.search(c.g, 3, 8)
+		if got.Outcome != Found || got.Distance != c.distWant {
+			t.Errorf("TestGenericSearches(%s): distWant=%v, got=%+v", c.name, c.distWant, got)
+		}
+		if len(got.Path) != 6 || got.Path[0] != 3 || got.Path[5] != 8 {
+			t.Errorf("TestGenericSearches(%s): unexpected path=%v", c.name, got.Path)
+		}
+	}
+	// A* expands fewer nodes than Dijkstra given a useful heuristic
+	var aStar, dijkstra Stats
+	AStar[int, float64](context.Background(), testWalls, 0, 3, Options{Stats: &aStar})
+	Dijkstra[int, float64](context.Background(), testWalls, 0, 3, Options{Stats: &dijkstra})
+	if aStar.Expanded >= dijkstra.Expanded {
+		t.Errorf("TestGenericSearches: A* expanded=%d, Dijkstra expanded=%d", aStar.Expanded, dijkstra.Expanded)
+	}
+}
+
+func TestNodesAdapter(t *testing.T) {
+	chain := testChain(4)
+	got := AStar[GraphNode, int](context.Background(), Nodes{}, chain[0], chain[3], Options{})
+	if !reflect.DeepEqual(got.Path, chain) {
+		t.Errorf("TestNodesAdapter: pathWant=%v, pathGot=%v", chain, got.Path)
+	}
+	bfs := BFS[GraphNode](context.Background(), Nodes{}, chain[0], chain[3], Options{})
+	if !reflect.DeepEqual(bfs.Path, chain) {
+		t.Errorf("TestNodesAdapter: BFS pathWant=%v, pathGot=%v", chain, bfs.Path)
+	}
+}
@@ -0,0 +1,65 @@
+package search
+
+// the algorithms a search may use, and the ways of costing its steps,
+// chosen by Options.Engine and Options.Cost
+
+// the algorithm used by AStar and its variants
+type Engine int
+
+const (
+	// A*, guided by the graph's estimate
+	EngineAStar Engine = iota
+	// Dijkstra's algorithm, which ignores the estimate, and so finds
+	// a shortest path even where the estimate is inadmissible
+	EngineDijkstra
+	// a breadth-first search, finding the path of fewest hops; it ignores
+	// costs, the estimate and TieBreak
+	EngineBFS
+)
+
+// how the steps of a path are costed
+type CostModel int
+
+const (
+	// by the graph's Cost
+	CostGraph CostModel = iota
+	// by the graph's WeightedCost; the graph must implement Weighted
+	CostWeighted
+)
+
+// Weighted may be implemented by a Graph to support CostWeighted,
+// an alternative costing of its steps, such as by their kind;
+// its Estimate must not overestimate these costs either
+type Weighted[N comparable, C Cost] interface {
+	WeightedCost(from, to N) C
+}
+
+// g's Weighted costs under model, or nil where steps cost the graph's Cost;
+// panics if g lacks the interface model requires
+func weightedCost[N comparable, C Cost](g Graph[N, C], model CostModel) Weighted[N, C] {
+	if model != CostWeighted {
+		return nil
+	}
+	if z, ok := g.(zeroEstimate[N, C]); ok {
+		g = z.Graph // Dijkstra's wrapper hides the graph's other methods
+	}
+	weighted, ok := g.(Weighted[N, C])
+	if !ok {
+		panic("search: CostWeighted requires a graph implementing Weighted")
+	}
+	return weighted
+}
+
+// the cost of a step, by weighted as chosen by weightedCost;
+// a method value of g.Cost would cost an allocation per search
+func stepCost[N comparable, C Cost](g Graph[N, C], weighted Weighted[N, C], from, to N) C {
+	if weighted != nil {
+		return weighted.WeightedCost(from, to)
+	}
+	return g.Cost(from, to)
+}
+
+// result with its distance converted to the cost type C
+func withCost[N comparable, C Cost](result Result[N, int]) Result[N, C] {
+	return Result[N, C]{result.Path, C(result.Distance), result.Outcome, result.Err}
+}
@@ -0,0 +1,62 @@
+package search
+
+import (
+		"testing"
+		"reflect"
+		"context"
+)
+
+// testWalls, where stepping into the top row costs 10 under CostWeighted
+type tollGrid struct {
+	testGrid
+}
+
+func (g tollGrid) WeightedCost(from, to int) float64 {
+	if to < g.width {
+		return 10
+	}
+	return 1
+}
+
+func TestEngines(t *testing.T) {
+	weighted := testWalls
+	weighted.weight = 2.5
+	cases := []struct {
+		name string
+		g Graph[int, float64]
+		opts Options
+		pathWant []int
+		distWant float64
+	}{
+		{"astar", testWalls, Options{}, []int{3, 2, 1, 0, 4, 8}, 5},
+		{"dijkstra", testWalls, Options{Engine: EngineDijkstra}, []int{3, 2, 1, 0, 4, 8}, 5},
+		{"bfs", weighted, Options{Engine: EngineBFS}, []int{3, 2, 1, 0, 4, 8}, 5}, // hops, not costs
+		{"weighted", tollGrid{testWalls}, Options{Cost: CostWeighted}, []int{3, 7, 11, 10, 9, 8}, 5},
+		{"weighted dijkstra", tollGrid{testWalls}, Options{Cost: CostWeighted, Engine: EngineDijkstra}, []int{3, 7, 11, 10, 9, 8}, 5},
+	}
+	for _, c := range cases {
+		got := AStar(context.Background(), c.g, 3, 8, c.opts)
+		if got.Outcome != Found || got.Distance != c.distWant || len(got.Path) != len(c.pathWant) ||
+			(c.opts.Cost == CostWeighted && !reflect.DeepEqual(got.Path, c.pathWant)) {
+			t.Errorf("TestEngines(%s): want=%v %v, got=%+v", c.name, c.pathWant, c.distWant, got)
+		}
+	}
+	var aStar, dijkstra Stats
+	AStar[int, float64](context.Background(), testWalls, 0, 3, Options{Stats: &aStar})
+	AStar[int, float64](context.Background(), testWalls, 0, 3, Options{Engine: EngineDijkstra, Stats: &dijkstra})
+	if aStar.Expanded >= dijkstra.Expanded {
+		t.Errorf("TestEngines: A* expanded=%d, EngineDijkstra expanded=%d", aStar.Expanded, dijkstra.Expanded)
+	}
+
+	// the nearest of several sources
+	if got := BFSMulti[int](context.Background(), testWalls, []int{11, 0}, []int{8}, Options{}); !reflect.DeepEqual(got.Path, []int{0, 4, 8}) {
+		t.Errorf("TestEngines: BFSMulti pathWant=[0 4 8], got=%v", got.Path)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("TestEngines: CostWeighted without Weighted, want panic")
+		}
+	}()
+	AStar[int, float64](context.Background(), testWalls, 3, 8, Options{Cost: CostWeighted})
+}
@@ -11,33 +11,40 @@ package search
 // 	index int
 // }
 // For this usage we'll substitute the aStarNode (see aStar.go):
-type item aStarNode
+type item[N comparable, C Cost] aStarNode[N, C]
 
 // a PriorityQueue implements heap.Interface and holds items.
-type PriorityQueue []*item
+type PriorityQueue[N comparable, C Cost] []*item[N, C]
 
-func (pq PriorityQueue) Len() int { return len(pq) }
+func (pq PriorityQueue[N, C]) Len() int { return len(pq) }
 
 // we want Pop() to give us the highest priority item first
-// we use rank, so a lower number means a higher priority
-func (pq PriorityQueue) Less(i, j int) bool {
-	return pq[i].rank < pq[j].rank
+// we use rank, so a lower number means a higher priority;
+// ties are broken by tie, then by seq
+func (pq PriorityQueue[N, C]) Less(i, j int) bool {
+	if pq[i].rank != pq[j].rank {
+		return pq[i].rank < pq[j].rank
+	}
+	if pq[i].tie != pq[j].tie {
+		return pq[i].tie < pq[j].tie
+	}
+	return pq[i].seq < pq[j].seq
 }
 
-func (pq PriorityQueue) Swap(i, j int) {
+func (pq PriorityQueue[N, C]) Swap(i, j int) {
 	pq[i], pq[j] = pq[j], pq[i]
 	pq[i].index = i
 	pq[j].index = j
 }
 
-func (pq *PriorityQueue) Push(x interface{}) {
+func (pq *PriorityQueue[N, C]) Push(x interface{}) {
 	n := len(*pq)
-	item := x.(*item)
+	item := x.(*item[N, C])
 	item.index = n
 	*pq = append(*pq, item)
 }
 
-func (pq *PriorityQueue) Pop() interface{} {
+func (pq *PriorityQueue[N, C]) Pop() interface{} {
 	old := *pq
 	n := len(old)
 	item := old[n-1]
@@ -6,17 +6,17 @@ import (
 )
 
 func TestPushPopPriority(t *testing.T) {
-	queue := &PriorityQueue{}
+	queue := &PriorityQueue[string, int]{}
 	heap.Init(queue)
 	cases := []struct {
-		in []*item
+		in []*item[string, int]
 	}{
-		{[]*item{&item{rank: 1}, &item{rank: 2}, &item{rank: 3},},},
-		{[]*item{&item{rank: 1}, &item{rank: 3}, &item{rank: 2},},},
-		{[]*item{&item{rank: 2}, &item{rank: 1}, &item{rank: 3},},},
-		{[]*item{&item{rank: 2}, &item{rank: 3}, &item{rank: 1},},},
-		{[]*item{&item{rank: 3}, &item{rank: 1}, &item{rank: 2},},},
-		{[]*item{&item{rank: 3}, &item{rank: 2}, &item{rank: 1},},},
+		{[]*item[string, int]{&item[string, int]{rank: 1}, &item[string, int]{rank: 2}, &item[string, int]{rank: 3},},},
+		{[]*item[string, int]{&item[string, int]{rank: 1}, &item[string, int]{rank: 3}, &item[string, int]{rank: 2},},},
+		{[]*item[string, int]{&item[string, int]{rank: 2}, &item[string, int]{rank: 1}, &item[string, int]{rank: 3},},},
+		{[]*item[string, int]{&item[string, int]{rank: 2}, &item[string, int]{rank: 3}, &item[string, int]{rank: 1},},},
+		{[]*item[string, int]{&item[string, int]{rank: 3}, &item[string, int]{rank: 1}, &item[string, int]{rank: 2},},},
+		{[]*item[string, int]{&item[string, int]{rank: 3}, &item[string, int]{rank: 2}, &item[string, int]{rank: 1},},},
 	}
 	for _, c := range cases {
 		for _, item := range c.in {
@@ -25,7 +25,7 @@ func TestPushPopPriority(t *testing.T) {
 		wantRank := 0
 		for queue.Len() > 0 {
 			wantRank++
-			got := heap.Pop(queue).(*item)
+			got := heap.Pop(queue).(*item[string, int])
 			if wantRank != got.rank {
 				t.Errorf("TestPushPopPriority: wantRank=%d, got.Rank=%d", wantRank, got.rank)
 			}
@@ -33,11 +33,11 @@ func TestPushPopPriority(t *testing.T) {
 	}
 
 	// so what happens if priorities are equal?
-	nodeA := &aStarNode{cost: 2, rank: 1}
-	nodeB := &aStarNode{cost: 1, rank: 1}
-	heap.Push(queue, (*item)(nodeA))
-	heap.Push(queue, (*item)(nodeB))
-	nodeOut := (*aStarNode)(heap.Pop(queue).(*item))
+	nodeA := &aStarNode[string, int]{cost: 2, rank: 1}
+	nodeB := &aStarNode[string, int]{cost: 1, rank: 1}
+	heap.Push(queue, (*item[string, int])(nodeA))
+	heap.Push(queue, (*item[string, int])(nodeB))
+	nodeOut := (*aStarNode[string, int])(heap.Pop(queue).(*item[string, int]))
 	if nodeOut.cost != 2 {
 		t.Errorf("TestPushPopPriority: equal rank items not popped by add order")
 	}
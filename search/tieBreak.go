@@ -0,0 +1,190 @@
+package search
+
+import (
+		"slices"
+)
+
+// how a search chooses between paths of equal cost;
+// every choice is deterministic for a given graph and Options
+type TieBreak int
+
+const (
+	// nodes of equal rank are expanded in the order they were generated,
+	// so the path depends only on the order of each node's neighbours
+	TieFirst TieBreak = iota
+	// the path whose nodes are smallest under the graph's Orderer,
+	// comparing them one by one from the start; the graph must be an Orderer
+	TieLexicographic
+	// as TieLexicographic, but preferring the more frequent node at each
+	// step, then the smaller if the graph is also an Orderer;
+	// the graph must implement Frequencies
+	TieFrequency
+	// nodes of equal rank with the higher estimate,
+	// and so the lower cost so far, are expanded first
+	TieHigherEstimate
+	// nodes of equal rank are expanded in an order drawn from Options.Seed
+	TieRandom
+)
+
+// Orderer may be implemented by a Graph to support TieLexicographic
+type Orderer[N comparable] interface {
+
+	// true if a sorts before b
+	Less(a, b N) bool
+}
+
+// Frequencies may be implemented by a Graph to support TieFrequency
+type Frequencies[N comparable] interface {
+
+	// how common a node is; higher is preferred, and unknown nodes are zero
+	Frequency(node N) float64
+}
+
+// a comparison of nodes for choosing between paths, negative if a is
+// preferred; nil if tieBreak chooses by expansion order alone;
+// panics if g lacks the interface tieBreak requires
+func nodeOrder[N comparable, C Cost](g Graph[N, C], tieBreak TieBreak) func(a, b N) int {
+	if tieBreak != TieLexicographic && tieBreak != TieFrequency {
+		return nil // before building byOrder, which would cost an allocation
+	}
+	if z, ok := g.(zeroEstimate[N, C]); ok {
+		g = z.Graph // Dijkstra's wrapper hides the graph's other methods
+	}
+	orderer, ordered := g.(Orderer[N])
+	byOrder := func(a, b N) int {
+		switch {
+		case orderer.Less(a, b):
+			return -1
+		case orderer.Less(b, a):
+			return 1
+		}
+		return 0
+	}
+	switch tieBreak {
+	case TieLexicographic:
+		if !ordered {
+			panic("search: TieLexicographic requires a graph implementing Orderer")
+		}
+		return byOrder
+	case TieFrequency:
+		frequencies, ok := g.(Frequencies[N])
+		if !ok {
+			panic("search: TieFrequency requires a graph implementing Frequencies")
+		}
+		return func(a, b N) int {
+			fa, fb := frequencies.Frequency(a), frequencies.Frequency(b)
+			switch {
+			case fa > fb:
+				return -1
+			case fa < fb:
+				return 1
+			case ordered:
+				return byOrder(a, b)
+			}
+			return 0
+		}
+	}
+	return nil
+}
+
+// sets the queue order of a node about to be pushed
+func (ws *Workspace[N, C]) tieBreak(node *aStarNode[N, C], opts Options) {
+	node.tie = 0
+	switch opts.TieBreak {
+	case TieHigherEstimate:
+		node.tie = node.cost
+	case TieRandom:
+		node.seq = ws.rng.Uint64()
+		return
+	}
+	ws.seq++
+	node.seq = ws.seq
+}
+
+// the path from a source to node, built in buffer
+func (ws *Workspace[N, C]) pathTo(node *aStarNode[N, C], buffer []N) []N {
+	buffer = buffer[:0]
+	for curr := node; curr != nil; curr = curr.parent {
+		buffer = append(buffer, curr.graphNode)
+	}
+	slices.Reverse(buffer)
+	return buffer
+}
+
+// compares the paths to a and b, each followed by then, node by node;
+// where one path is the start of the other, the shorter is preferred
+func (ws *Workspace[N, C]) comparePaths(a, b *aStarNode[N, C], order func(a, b N) int, then ...N) int {
+	ws.pathA = append(ws.pathTo(a, ws.pathA), then...)
+	ws.pathB = append(ws.pathTo(b, ws.pathB), then...)
+	for i := 0; i < len(ws.pathA) && i < len(ws.pathB); i++ {
+		if c := order(ws.pathA[i], ws.pathB[i]); c != 0 {
+			return c
+		}
+	}
+	return len(ws.pathA) - len(ws.pathB)
+}
+
+// rewires the parents of the expanded nodes so that each is reached by its
+// preferred shortest path under weighted, the costing the search used, then
+// returns the preferred target reached at the cost of reached, the first
+// target the search reached; every node on a shortest path must have been
+// expanded, and if the rewiring reaches no target (as rounding in
+// floating-point costs could cause), reached is returned as it was found
+func (ws *Workspace[N, C]) preferredTarget(g Graph[N, C], indexer Indexer[N], order func(a, b N) int, weighted Weighted[N, C], reached *aStarNode[N, C]) (best *aStarNode[N, C]) {
+	// a node's predecessors on its shortest paths all cost less,
+	// so visiting in order of cost settles each before its successors
+	slices.SortStableFunc(ws.expanded, func(a, b *aStarNode[N, C]) int {
+		switch {
+		case a.cost < b.cost:
+			return -1
+		case a.cost > b.cost:
+			return 1
+		}
+		return 0
+	})
+	ws.links = ws.links[:0]
+	for _, node := range ws.expanded {
+		if node.parent != nil {
+			ws.links = append(ws.links, link[N, C]{node, node.parent, node.depth})
+			node.parent, node.depth = nil, -1 // unreached, distinct from a source
+		}
+	}
+	for _, current := range ws.expanded {
+		if current.depth < 0 {
+			continue
+		}
+		for _, neighbor := range g.Neighbours(current.graphNode) {
+			neighborNode := ws.get(indexer, neighbor)
+			if !neighborNode.closed || neighborNode.depth == 0 ||
+				current.cost + stepCost(g, weighted, current.graphNode, neighbor) != neighborNode.cost {
+				continue
+			}
+			if neighborNode.depth < 0 || ws.comparePaths(current, neighborNode.parent, order, neighbor) < 0 {
+				neighborNode.parent = current
+				neighborNode.depth = current.depth + 1
+			}
+		}
+	}
+	for _, node := range ws.expanded {
+		if !ws.targets[node.graphNode] || node.cost != reached.cost || node.depth < 0 {
+			continue
+		}
+		if best == nil || ws.comparePaths(node, best, order) < 0 {
+			best = node
+		}
+	}
+	if best == nil {
+		for _, l := range ws.links {
+			l.node.parent, l.node.depth = l.parent, l.depth
+		}
+		return reached
+	}
+	return
+}
+
+// a node's parent and depth as the search found them,
+// kept while rewiring in case they must be restored
+type link[N comparable, C Cost] struct {
+	node, parent *aStarNode[N, C]
+	depth int
+}
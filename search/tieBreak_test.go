@@ -0,0 +1,144 @@
+package search
+
+import (
+		"testing"
+		"reflect"
+		"context"
+		"math/rand"
+)
+
+// a testGrid whose neighbours are returned in a shuffled order,
+// as they might be if they came from iterating over a map;
+// nodes are ordered by ID, and the frequency of a node is its ID
+type shuffledGrid struct {
+	testGrid
+	rng *rand.Rand
+}
+
+func (g shuffledGrid) Neighbours(node int) []int {
+	neighbours := g.testGrid.Neighbours(node)
+	g.rng.Shuffle(len(neighbours), func(i, j int) {
+		neighbours[i], neighbours[j] = neighbours[j], neighbours[i]
+	})
+	return neighbours
+}
+
+func (g shuffledGrid) Less(a, b int) bool {
+	return a < b
+}
+
+func (g shuffledGrid) Frequency(node int) float64 {
+	return float64(node)
+}
+
+// a graph where every node is equally frequent, so that TieFrequency
+// falls back to the graph's order
+type uniformGrid struct {
+	shuffledGrid
+}
+
+func (g uniformGrid) Frequency(node int) float64 {
+	return 1
+}
+
+func TestTieBreak(t *testing.T) {
+	// a 4x4 grid without walls has twenty shortest paths from 0 to 15
+	open := testGrid{width: 4, height: 4}
+	cases := []struct {
+		tieBreak TieBreak
+		want []int
+	}{
+		{TieLexicographic, []int{0, 1, 2, 3, 7, 11, 15}},
+		{TieFrequency, []int{0, 4, 8, 12, 13, 14, 15}},
+	}
+	for _, c := range cases {
+		for run := int64(0); run < 20; run++ {
+			g := shuffledGrid{open, rand.New(rand.NewSource(run))}
+			got := AStar[int, float64](context.Background(), g, 0, 15, Options{TieBreak: c.tieBreak})
+			if !reflect.DeepEqual(got.Path, c.want) {
+				t.Errorf("TestTieBreak(%d, run %d): want=%v, got=%v", c.tieBreak, run, c.want, got.Path)
+			}
+			got = Dijkstra[int, float64](context.Background(), g, 0, 15, Options{TieBreak: c.tieBreak})
+			if !reflect.DeepEqual(got.Path, c.want) {
+				t.Errorf("TestTieBreak(Dijkstra %d, run %d): want=%v, got=%v", c.tieBreak, run, c.want, got.Path)
+			}
+		}
+	}
+	uniform := uniformGrid{shuffledGrid{open, rand.New(rand.NewSource(1))}}
+	got := AStar[int, float64](context.Background(), uniform, 0, 15, Options{TieBreak: TieFrequency})
+	if want := []int{0, 1, 2, 3, 7, 11, 15}; !reflect.DeepEqual(got.Path, want) {
+		t.Errorf("TestTieBreak(uniform frequencies): want=%v, got=%v", want, got.Path)
+	}
+	// several sources, where the preferred path starts from the larger
+	got = AStarMulti[int, float64](context.Background(), shuffledGrid{open, rand.New(rand.NewSource(1))},
+		[]int{5, 3}, []int{15}, Options{TieBreak: TieFrequency})
+	if want := []int{3, 7, 11, 15}; !reflect.DeepEqual(got.Path, want) {
+		t.Errorf("TestTieBreak(multiple sources): want=%v, got=%v", want, got.Path)
+	}
+}
+
+// a shuffledGrid where, under CostWeighted, every step costs 2
+// and stepping into the top row costs 10
+type tolledGrid struct {
+	shuffledGrid
+}
+
+func (g tolledGrid) WeightedCost(from, to int) float64 {
+	if to < g.width {
+		return 10
+	}
+	return 2
+}
+
+// the preferred path is chosen among the cheapest by the cost the search
+// used, rather than the graph's Cost
+func TestTieBreakWeighted(t *testing.T) {
+	open := testGrid{width: 4, height: 4}
+	cases := []struct {
+		tieBreak TieBreak
+		want []int
+	}{
+		{TieLexicographic, []int{0, 4, 5, 6, 7, 11, 15}}, // avoiding the top row
+		{TieFrequency, []int{0, 4, 8, 12, 13, 14, 15}},
+	}
+	for _, c := range cases {
+		for run := int64(0); run < 20; run++ {
+			g := tolledGrid{shuffledGrid{open, rand.New(rand.NewSource(run))}}
+			for _, engine := range []Engine{EngineAStar, EngineDijkstra} {
+				opts := Options{TieBreak: c.tieBreak, Cost: CostWeighted, Engine: engine}
+				got := AStar[int, float64](context.Background(), g, 0, 15, opts)
+				if !reflect.DeepEqual(got.Path, c.want) || got.Distance != 12 {
+					t.Errorf("TestTieBreakWeighted(%+v, run %d): want=%v, got=%+v", opts, run, c.want, got)
+				}
+			}
+		}
+	}
+}
+
+func TestTieBreakRepeatable(t *testing.T) {
+	open := testGrid{width: 6, height: 6}
+	for _, opts := range []Options{
+		{TieBreak: TieFirst},
+		{TieBreak: TieHigherEstimate},
+		{TieBreak: TieRandom, Seed: 7},
+		{TieBreak: TieRandom, Seed: 8},
+	} {
+		want := AStar[int, float64](context.Background(), open, 0, 35, opts)
+		ws := new(Workspace[int, float64])
+		for run := 0; run < 20; run++ {
+			got := ws.AStar(context.Background(), open, 0, 35, opts)
+			if !reflect.DeepEqual(got.Path, want.Path) || got.Distance != 10 {
+				t.Errorf("TestTieBreakRepeatable(%+v, run %d): want=%v, got=%v", opts, run, want.Path, got.Path)
+			}
+		}
+	}
+}
+
+func TestTieBreakUnsupported(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("TestTieBreakUnsupported: want panic for a graph without Orderer")
+		}
+	}()
+	AStar[int, float64](context.Background(), testWalls, 3, 8, Options{TieBreak: TieLexicographic})
+}
@@ -0,0 +1,268 @@
+package search
+
+import (
+		"context"
+		"container/heap"
+		"math/rand"
+)
+
+// Indexer may be implemented by a Graph whose nodes have dense integer IDs,
+// so that a Workspace can keep search state in arrays rather than a map
+type Indexer[N comparable] interface {
+
+	// the ID of node, from 0 to Len()-1
+	Index(node N) int
+
+	// the number of IDs
+	Len() int
+}
+
+// the number of aStarNodes allocated at a time for graphs without an Indexer
+const chunkSize = 1024
+
+// Workspace holds the memory used by a search, so that it can be reused;
+// after the first few searches of a graph, searching produces almost no
+// garbage beyond the returned path;
+// the zero value is ready to use, and a Workspace may be kept in a
+// sync.Pool, but it must not be used by more than one search at a time
+type Workspace[N comparable, C Cost] struct {
+	// for graphs implementing Indexer:
+	// a node is only valid if its generation is the current one,
+	// so nothing needs clearing between searches
+	dense []aStarNode[N, C]
+	generations []uint32
+	generation uint32
+
+	// for other graphs: nodes are handed out from fixed-size chunks,
+	// which never move, so pointers to them stay valid
+	sparse map[N]*aStarNode[N, C]
+	chunks [][]aStarNode[N, C]
+	used int
+
+	queue PriorityQueue[N, C]
+	targets map[N]bool
+
+	// for tie-breaking
+	seq uint64
+	rng *rand.Rand
+	expanded []*aStarNode[N, C] // only kept when comparing paths
+	links []link[N, C]
+	pathA, pathB []N
+}
+
+// prepares the workspace for a search of g,
+// returning g's Indexer if it has one
+func (ws *Workspace[N, C]) reset(g Graph[N, C], opts Options) Indexer[N] {
+	ws.queue = ws.queue[:0]
+	ws.expanded = ws.expanded[:0]
+	ws.seq = 0
+	if opts.TieBreak == TieRandom {
+		if ws.rng == nil {
+			ws.rng = rand.New(rand.NewSource(opts.Seed))
+		} else {
+			ws.rng.Seed(opts.Seed)
+		}
+	}
+	if ws.targets == nil {
+		ws.targets = make(map[N]bool)
+	}
+	clear(ws.targets)
+	if indexer, ok := g.(Indexer[N]); ok {
+		if n := indexer.Len(); len(ws.dense) < n {
+			ws.dense = make([]aStarNode[N, C], n)
+			ws.generations = make([]uint32, n)
+			ws.generation = 0
+		}
+		ws.generation++
+		if ws.generation == 0 {
+			// wrapped around, so old generations could be mistaken for current
+			clear(ws.generations)
+			ws.generation = 1
+		}
+		return indexer
+	}
+	if ws.sparse == nil {
+		ws.sparse = make(map[N]*aStarNode[N, C])
+	}
+	clear(ws.sparse)
+	ws.used = 0
+	return nil
+}
+
+// gets the graph node wrapped in an aStarNode,
+// instantiating if required.
+func (ws *Workspace[N, C]) get(indexer Indexer[N], gn N) *aStarNode[N, C] {
+	if indexer != nil {
+		id := indexer.Index(gn)
+		node := &ws.dense[id]
+		if ws.generations[id] != ws.generation {
+			*node = aStarNode[N, C]{graphNode: gn}
+			ws.generations[id] = ws.generation
+		}
+		return node
+	}
+	node, ok := ws.sparse[gn]
+	if !ok {
+		chunk := ws.used / chunkSize
+		if chunk == len(ws.chunks) {
+			ws.chunks = append(ws.chunks, make([]aStarNode[N, C], chunkSize))
+		}
+		node = &ws.chunks[chunk][ws.used%chunkSize]
+		*node = aStarNode[N, C]{graphNode: gn}
+		ws.used++
+		ws.sparse[gn] = node
+	}
+	return node
+}
+
+// as the AStar function, but reusing the workspace's memory
+func (ws *Workspace[N, C]) AStar(ctx context.Context, g Graph[N, C], from, to N, opts Options) Result[N, C] {
+	return ws.AStarMulti(ctx, g, []N{from}, []N{to}, opts)
+}
+
+// as the AStarMulti function, but reusing the workspace's memory
+func (ws *Workspace[N, C]) AStarMulti(ctx context.Context, g Graph[N, C], from, to []N, opts Options) (result Result[N, C]) {
+	if opts.Engine == EngineBFS {
+		return withCost[N, C](BFSMulti[N](ctx, g, from, to, opts))
+	}
+	stats := opts.Stats
+	if stats != nil {
+		*stats = Stats{}
+		defer stats.timer()()
+	}
+	if len(to) == 0 {
+		return
+	}
+	indexer := ws.reset(g, opts)
+	weighted := weightedCost(g, opts.Cost)
+	order := nodeOrder(g, opts.TieBreak)
+	for _, target := range to {
+		ws.targets[target] = true
+	}
+	estimate := func(node N) C {
+		if opts.Engine == EngineDijkstra {
+			return 0
+		}
+		best := g.Estimate(node, to[0])
+		for _, target := range to[1:] {
+			if e := g.Estimate(node, target); e < best {
+				best = e
+			}
+		}
+		return best
+	}
+	queue := &ws.queue
+	for _, source := range from {
+		fromNode := ws.get(indexer, source)
+		if fromNode.open {
+			continue // listed twice
+		}
+		fromNode.open = true
+		fromNode.rank = estimate(source)
+		ws.tieBreak(fromNode, opts)
+		heap.Push(queue, (*item[N, C])(fromNode))
+	}
+	if stats != nil {
+		stats.Generated, stats.PeakQueue = queue.Len(), queue.Len()
+	}
+	done := ctx.Done()
+	pruned := false
+	expanded := 0 // counted even without stats, for MaxExpanded
+	var reached *aStarNode[N, C] // the first target, when comparing paths
+	for {
+		if reached != nil && (queue.Len() == 0 || (*queue)[0].rank > reached.cost) {
+			// every shortest path has been explored, so choose between them
+			ws.found(&result, ws.preferredTarget(g, indexer, order, weighted, reached))
+			return
+		}
+		if queue.Len() == 0 {
+			// there's no path, unless one was pruned by depth
+			if pruned {
+				result.Outcome, result.Err = Aborted, ErrMaxDepth
+			}
+			return
+		}
+		select {
+		case <-done:
+			result.Outcome, result.Err = Aborted, ctx.Err()
+			return
+		default:
+		}
+		if reached == nil && opts.MaxExpanded > 0 && expanded >= opts.MaxExpanded {
+			result.Outcome, result.Err = Aborted, ErrMaxExpanded
+			return
+		}
+		// priority queue does its magic:
+		current := (*aStarNode[N, C])(heap.Pop(queue).(*item[N, C]))
+		current.open = false
+		current.closed = true
+		expanded++
+		if stats != nil {
+			stats.Expanded = expanded
+			stats.Depth = max(stats.Depth, current.depth)
+		}
+		if order != nil {
+			ws.expanded = append(ws.expanded, current)
+		}
+
+		if ws.targets[current.graphNode] {
+			// found a path to the goal!
+			if order == nil {
+				ws.found(&result, current)
+				return
+			}
+			// but other paths of the same cost may be preferred
+			if reached == nil {
+				reached = current
+			}
+			continue
+		}
+
+		if opts.MaxDepth > 0 && current.depth >= opts.MaxDepth {
+			pruned = true
+			continue
+		}
+		for _, neighbor := range g.Neighbours(current.graphNode) {
+			cost := current.cost + stepCost(g, weighted, current.graphNode, neighbor)
+			neighborNode := ws.get(indexer, neighbor)
+			if cost < neighborNode.cost {
+				// this branch is for handling nodes
+				// that have already been assessed in previous iterations
+				// where the new cost is cheaper
+				if neighborNode.open {
+					heap.Remove(queue, neighborNode.index)
+				}
+				if neighborNode.closed && stats != nil {
+					stats.Reopened++
+				}
+				neighborNode.open = false
+				neighborNode.closed = false
+			}
+			if !neighborNode.open && !neighborNode.closed {
+				neighborNode.cost = cost
+				neighborNode.depth = current.depth + 1
+				neighborNode.open = true
+				neighborNode.rank = cost + estimate(neighbor)
+				neighborNode.parent = current
+				ws.tieBreak(neighborNode, opts)
+				heap.Push(queue, (*item[N, C])(neighborNode))
+				if stats != nil {
+					stats.Generated++
+					stats.PeakQueue = max(stats.PeakQueue, queue.Len())
+				}
+			}
+		}
+	}
+}
+
+// records the path to target in result
+func (ws *Workspace[N, C]) found(result *Result[N, C], target *aStarNode[N, C]) {
+	// depth is the number of hops, so the path can be filled
+	// from --> to without reversing
+	result.Path = make([]N, target.depth+1)
+	for curr := target; curr != nil; curr = curr.parent {
+		result.Path[curr.depth] = curr.graphNode
+	}
+	result.Distance = target.cost
+	result.Outcome = Found
+}
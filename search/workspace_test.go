@@ -0,0 +1,99 @@
+package search
+
+import (
+		"testing"
+		"reflect"
+		"context"
+)
+
+// a testGrid whose node IDs are already dense, so that a Workspace
+// keeps its state in arrays rather than a map
+type indexedGrid struct {
+	testGrid
+}
+
+func (g indexedGrid) Index(node int) int {
+	return node
+}
+
+func (g indexedGrid) Len() int {
+	return g.width * g.height
+}
+
+func TestWorkspace(t *testing.T) {
+	graphs := []struct {
+		name string
+		g Graph[int, float64]
+	}{
+		{"sparse", testWalls},
+		{"dense", indexedGrid{testWalls}},
+	}
+	pairs := [][2]int{{3, 8}, {0, 3}, {8, 8}, {11, 0}, {3, 8}}
+	for _, graph := range graphs {
+		ws := new(Workspace[int, float64])
+		for _, pair := range pairs {
+			want := AStar(context.Background(), testWalls, pair[0], pair[1], Options{})
+			got := ws.AStar(context.Background(), graph.g, pair[0], pair[1], Options{})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Workspace(%s).AStar(%d, %d), want=%+v, got=%+v", graph.name, pair[0], pair[1], want, got)
+			}
+		}
+	}
+	// a generation counter wrapping around must not revive stale nodes
+	ws := new(Workspace[int, float64])
+	g := indexedGrid{testWalls}
+	ws.AStar(context.Background(), g, 0, 11, Options{})
+	ws.generation = ^uint32(0)
+	ws.generations[0] = 1
+	if got := ws.AStar(context.Background(), g, 3, 8, Options{}); got.Distance != 5 || len(got.Path) != 6 {
+		t.Errorf("Workspace.AStar(after wrap), want distance 5, got=%+v", got)
+	}
+}
+
+// an indexedGrid whose neighbours are precomputed,
+// so that the graph itself allocates nothing during a search
+type tableGrid struct {
+	indexedGrid
+	table [][]int
+}
+
+func (g tableGrid) Neighbours(node int) []int {
+	return g.table[node]
+}
+
+// a 64x64 grid with a wall down the middle, open at the bottom
+func benchGrid() tableGrid {
+	g := testGrid{width: 64, height: 64, walls: make(map[int]bool)}
+	for y := 0; y < 60; y++ {
+		g.walls[y*64 + 32] = true
+	}
+	table := make([][]int, g.width*g.height)
+	for node := range table {
+		table[node] = g.Neighbours(node)
+	}
+	return tableGrid{indexedGrid{g}, table}
+}
+
+// a fresh workspace per search, as the AStar function uses;
+// the graph is wrapped so that it hides its Indexer
+func BenchmarkAStar(b *testing.B) {
+	g := benchGrid()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		AStar[int, float64](context.Background(), struct{ Graph[int, float64] }{g}, 0, 63, Options{})
+	}
+}
+
+// compare allocs/op with BenchmarkAStar;
+// the only allocation remaining is the returned path
+func BenchmarkWorkspace(b *testing.B) {
+	var g Graph[int, float64] = benchGrid() // boxed once, not per search
+	ws := new(Workspace[int, float64])
+	ws.AStar(context.Background(), g, 0, 63, Options{}) // to size the workspace
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ws.AStar(context.Background(), g, 0, 63, Options{})
+	}
+}
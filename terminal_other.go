@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package main
+
+import (
+		"errors"
+)
+
+// line editing needs raw terminal input, which is only supported on
+// Unix-like systems; elsewhere lines are read as typed
+func makeRaw(fd int) (restore func() error, err error) {
+	return nil, errors.New("raw terminal mode is not supported on this system")
+}